@@ -1,7 +1,6 @@
 package pages
 
 import (
-    "github.com/go-playground/validator/v10"
     "github.com/michalCapo/go-srui/ui"
 )
 
@@ -24,12 +23,7 @@ func (form *TLoginForm) Success(ctx *ui.Context) string {
 
 // Login action
 func (form *TLoginForm) Login(ctx *ui.Context) string {
-    if err := ctx.Body(form); err != nil {
-        return form.Render(ctx, &err)
-    }
-
-    v := validator.New()
-    if err := v.Struct(form); err != nil {
+    if err := ctx.Bind(form); err != nil {
         return form.Render(ctx, &err)
     }
 
@@ -1,7 +1,6 @@
 package pages
 
 import (
-    "github.com/go-playground/validator/v10"
     "github.com/michalCapo/go-srui/ui"
     "time"
 )
@@ -28,11 +27,7 @@ var (
 )
 
 func (f *DemoForm) Submit(ctx *ui.Context) string {
-    if err := ctx.Body(f); err != nil {
-        return f.Render(ctx, &err)
-    }
-    v := validator.New()
-    if err := v.Struct(f); err != nil {
+    if err := ctx.Bind(f); err != nil {
         return f.Render(ctx, &err)
     }
     ctx.Success("Form submitted successfully")
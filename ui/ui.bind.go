@@ -0,0 +1,481 @@
+package ui
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// BindError is posted value which can not be converted to the type of the field.
+type BindError struct {
+	Field  string
+	Value  string
+	Reason string
+}
+
+func (e BindError) Error() string {
+	return fmt.Sprintf("%s: %q %s", e.Field, e.Value, e.Reason)
+}
+
+// BindErrors is returned by ctx.Body, pass it to Error of inputs and to ErrorForm
+// like validator.ValidationErrors.
+type BindErrors []BindError
+
+func (e BindErrors) Error() string {
+	result := make([]string, len(e))
+
+	for i, err := range e {
+		result[i] = err.Error()
+	}
+
+	return strings.Join(result, "\n")
+}
+
+// fieldError finds error of the named field in BindErrors or validator.ValidationErrors.
+func fieldError(errs *error, name string) error {
+	if errs == nil || *errs == nil {
+		return nil
+	}
+
+	var binds BindErrors
+	if errors.As(*errs, &binds) {
+		for _, err := range binds {
+			if err.Field == name {
+				return err
+			}
+		}
+	}
+
+	var validations validator.ValidationErrors
+	if errors.As(*errs, &validations) {
+		for _, err := range validations {
+			if validationPath(err) == name {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validationPath converts namespace of validation error to the name used by inputs and PathValue,
+// e.g. TForm.Address.City to Address.City.
+func validationPath(err validator.FieldError) string {
+	_, path, ok := strings.Cut(err.StructNamespace(), ".")
+	if !ok {
+		return err.StructField()
+	}
+
+	return path
+}
+
+// Validator returns validator shared by ctx.Bind, register custom tags on it once at startup,
+// e.g. app.Validator().RegisterValidation("iban", validateIBAN).
+func (app *App) Validator() *validator.Validate {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	if app.validate == nil {
+		app.validate = validator.New()
+	}
+
+	return app.validate
+}
+
+// Bind decodes request into output by ctx.Body and validates it by app.Validator.
+// Conversion and validation errors are returned together, fields with BindError are not validated again.
+func (ctx *Context) Bind(output any) error {
+	var binds BindErrors
+
+	err := ctx.Body(output)
+	if err != nil && !errors.As(err, &binds) {
+		return err
+	}
+
+	validate := validator.New()
+	if ctx.App != nil {
+		validate = ctx.App.Validator()
+	}
+
+	err = validate.Struct(output)
+	if err == nil {
+		if len(binds) > 0 {
+			return binds
+		}
+
+		return nil
+	}
+
+	var validations validator.ValidationErrors
+	if !errors.As(err, &validations) {
+		return err
+	}
+
+	if len(binds) == 0 {
+		return validations
+	}
+
+	failed := make(map[string]bool, len(binds))
+	for _, bind := range binds {
+		failed[bind.Field] = true
+	}
+
+	var rest validator.ValidationErrors
+	for _, validation := range validations {
+		if !failed[validationPath(validation)] {
+			rest = append(rest, validation)
+		}
+	}
+
+	if len(rest) == 0 {
+		return binds
+	}
+
+	return errors.Join(binds, rest)
+}
+
+// errorReason renders the reason of BindError below the field, validation errors only highlight it.
+func errorReason(err error) string {
+	var bind BindError
+	if !errors.As(err, &bind) {
+		return ""
+	}
+
+	return Div("text-red-600 text-sm px-1")(bind.Reason)
+}
+
+// Binder converts posted value into a value of the registered type.
+type Binder = func(value string) (any, error)
+
+var binders = struct {
+	sync.RWMutex
+	types map[reflect.Type]Binder
+}{
+	types: make(map[reflect.Type]Binder),
+}
+
+// RegisterBinder sets conversion used by ctx.Body for fields of the given type,
+// e.g. ui.RegisterBinder(reflect.TypeOf(decimal.Decimal{}), func(value string) (any, error) { return decimal.NewFromString(value) }).
+func RegisterBinder(t reflect.Type, binder Binder) {
+	binders.Lock()
+	defer binders.Unlock()
+
+	binders.types[t] = binder
+}
+
+func binder(t reflect.Type) (Binder, bool) {
+	binders.RLock()
+	defer binders.RUnlock()
+
+	found, ok := binders.types[t]
+	return found, ok
+}
+
+// layouts of dates and times posted by inputs (date, time, datetime-local) and by action values
+var layouts = []string{
+	"2006-01-02 15:04:05 -0700 UTC",
+	"2006-01-02",
+	"15:04",
+	"2006-01-02T15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"15:04:05",
+	time.RFC3339,
+}
+
+func parseTime(value string) (time.Time, error) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("must be a date or time")
+}
+
+func init() {
+	RegisterBinder(reflect.TypeOf(gorm.DeletedAt{}), func(value string) (any, error) {
+		if value == "" {
+			return gorm.DeletedAt{}, nil
+		}
+
+		t, err := parseTime(value)
+		return gorm.DeletedAt{Time: t, Valid: err == nil}, err
+	})
+
+	RegisterBinder(reflect.TypeOf(sql.NullTime{}), func(value string) (any, error) {
+		if value == "" {
+			return sql.NullTime{}, nil
+		}
+
+		t, err := parseTime(value)
+		return sql.NullTime{Time: t, Valid: err == nil}, err
+	})
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	textType     = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	scannerType  = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// formatValue is the inverse of bindValue, it formats the field for action values.
+// Structs, slices and maps which can not be formatted are skipped.
+func formatValue(field reflect.Value) (string, bool) {
+	t := field.Type()
+
+	if t.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			return "", true
+		}
+
+		return formatValue(field.Elem())
+	}
+
+	if t == timeType {
+		return field.Interface().(time.Time).Format(time.RFC3339Nano), true
+	}
+
+	if t == durationType {
+		return time.Duration(field.Int()).String(), true
+	}
+
+	// copy the value, so methods with pointer receivers can be called
+	temp := reflect.New(t)
+	temp.Elem().Set(field)
+
+	if marshaler, ok := temp.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err == nil
+	}
+
+	if valuer, ok := temp.Interface().(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			return "", false
+		}
+
+		switch value := value.(type) {
+		case nil:
+			return "", true
+
+		case time.Time:
+			return value.Format(time.RFC3339Nano), true
+
+		case []byte:
+			return string(value), true
+
+		default:
+			return fmt.Sprintf("%v", value), true
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Map, reflect.Array, reflect.Interface, reflect.Func, reflect.Chan:
+		return "", false
+	}
+
+	return fmt.Sprintf("%v", field.Interface()), true
+}
+
+// bindValue converts the value by the type of the field and sets it.
+// Structs, slices and maps are skipped, they are bound field by field through paths.
+func bindValue(field reflect.Value, value string) error {
+	t := field.Type()
+
+	if found, ok := binder(t); ok {
+		result, err := found(value)
+		if err != nil {
+			return err
+		}
+
+		if result == nil {
+			field.Set(reflect.Zero(t))
+			return nil
+		}
+
+		v := reflect.ValueOf(result)
+		if !v.Type().AssignableTo(t) {
+			if !v.Type().ConvertibleTo(t) {
+				return fmt.Errorf("binder returned %s instead of %s", v.Type(), t)
+			}
+
+			v = v.Convert(t)
+		}
+
+		field.Set(v)
+		return nil
+	}
+
+	if t.Kind() == reflect.Pointer {
+		if !bindable(t.Elem()) {
+			return nil
+		}
+
+		if value == "" {
+			field.Set(reflect.Zero(t))
+			return nil
+		}
+
+		elem := reflect.New(t.Elem())
+		if err := bindValue(elem.Elem(), value); err != nil {
+			return err
+		}
+
+		field.Set(elem)
+		return nil
+	}
+
+	if t == timeType {
+		if value == "" {
+			field.Set(reflect.Zero(t))
+			return nil
+		}
+
+		parsed, err := parseTime(value)
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(parsed))
+		return nil
+	}
+
+	if t == durationType {
+		if value == "" {
+			field.SetInt(0)
+			return nil
+		}
+
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("must be a duration")
+		}
+
+		field.SetInt(int64(parsed))
+		return nil
+	}
+
+	if field.CanAddr() && reflect.PointerTo(t).Implements(textType) {
+		if value == "" {
+			field.Set(reflect.Zero(t))
+			return nil
+		}
+
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	if field.CanAddr() && reflect.PointerTo(t).Implements(scannerType) {
+		scanner := field.Addr().Interface().(sql.Scanner)
+
+		if value == "" {
+			return scanner.Scan(nil)
+		}
+
+		return scanner.Scan(value)
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		field.SetString(value)
+
+	case reflect.Bool:
+		if value == "" {
+			field.SetBool(false)
+			return nil
+		}
+
+		// checked checkbox without value attribute sends "on"
+		if value == "on" {
+			field.SetBool(true)
+			return nil
+		}
+
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("must be true or false")
+		}
+
+		field.SetBool(parsed)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value = strings.ReplaceAll(value, "_", "")
+		if value == "" {
+			field.SetInt(0)
+			return nil
+		}
+
+		parsed, err := strconv.ParseInt(value, 10, t.Bits())
+		if err != nil {
+			return numberError(err, "must be a whole number")
+		}
+
+		field.SetInt(parsed)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value = strings.ReplaceAll(value, "_", "")
+		if value == "" {
+			field.SetUint(0)
+			return nil
+		}
+
+		parsed, err := strconv.ParseUint(value, 10, t.Bits())
+		if err != nil {
+			return numberError(err, "must be a positive whole number")
+		}
+
+		field.SetUint(parsed)
+
+	case reflect.Float32, reflect.Float64:
+		value = strings.ReplaceAll(value, "_", "")
+		if value == "" {
+			field.SetFloat(0)
+			return nil
+		}
+
+		parsed, err := strconv.ParseFloat(value, t.Bits())
+		if err != nil {
+			return numberError(err, "must be a number")
+		}
+
+		field.SetFloat(parsed)
+
+	case reflect.Struct, reflect.Slice, reflect.Map, reflect.Array, reflect.Interface:
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported type %s", t)
+	}
+
+	return nil
+}
+
+// bindable reports whether the type is converted from a single value, other structs, slices and maps are bound by paths.
+func bindable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Map, reflect.Array, reflect.Interface:
+		_, ok := binder(t)
+		return ok || t == timeType || reflect.PointerTo(t).Implements(textType) || reflect.PointerTo(t).Implements(scannerType)
+	}
+
+	return true
+}
+
+func numberError(err error, reason string) error {
+	if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+		return fmt.Errorf("number is out of range")
+	}
+
+	return fmt.Errorf("%s", reason)
+}
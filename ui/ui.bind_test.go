@@ -0,0 +1,187 @@
+package ui
+
+import (
+	"database/sql"
+	"html"
+	"io"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type record struct {
+	ID      int
+	Count   *int
+	Parent  *int
+	Note    sql.NullString
+	Empty   sql.NullString
+	Created sql.NullTime
+	Deleted gorm.DeletedAt
+	At      time.Time
+	Wait    time.Duration
+	Tags    []string
+}
+
+// action values rendered by ctx.Call are posted back and bound by ctx.Body
+func TestCallBodyRoundTrip(t *testing.T) {
+	count := 5
+	input := record{
+		ID:      3,
+		Count:   &count,
+		Note:    sql.NullString{String: "hi", Valid: true},
+		Created: sql.NullTime{Time: time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC), Valid: true},
+		At:      time.Date(2023, 5, 6, 7, 8, 9, 0, time.UTC),
+		Wait:    time.Second,
+		Tags:    []string{"a"},
+	}
+
+	var output record
+	var err error
+
+	app := MakeApp("en")
+	action := func(ctx *Context) string {
+		err = ctx.Body(&output)
+		return ""
+	}
+
+	var rendered string
+	app.Page("/", func(ctx *Context) string {
+		rendered = ctx.Call(action, input).None()
+		return rendered
+	})
+
+	handler := app.Handler()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	path := regexp.MustCompile(`"(/[^"]+)"`).FindStringSubmatch(html.UnescapeString(rendered))
+	values := regexp.MustCompile(`\[\{.*\}\]`).FindString(html.UnescapeString(rendered))
+	if path == nil || values == "" {
+		t.Fatalf("action not rendered: %s", rendered)
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", path[1], strings.NewReader(values)))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if output.ID != 3 || output.Count == nil || *output.Count != 5 || output.Parent != nil {
+		t.Errorf("numbers not bound: %+v", output)
+	}
+
+	if output.Note != input.Note || output.Empty.Valid {
+		t.Errorf("sql.NullString not bound: %+v %+v", output.Note, output.Empty)
+	}
+
+	if !output.Created.Valid || !output.Created.Time.Equal(input.Created.Time) || output.Deleted.Valid {
+		t.Errorf("times not bound: %+v %+v", output.Created, output.Deleted)
+	}
+
+	if !output.At.Equal(input.At) || output.Wait != time.Second || output.Tags != nil {
+		t.Errorf("values not bound: %+v", output)
+	}
+}
+
+func TestBodyErrors(t *testing.T) {
+	type form struct {
+		Age  int
+		Name string
+	}
+
+	var output form
+	var err error
+
+	app := MakeApp("en")
+	action := func(ctx *Context) string {
+		err = ctx.Body(&output)
+		return ""
+	}
+
+	app.Register("POST", "/save", &action)
+
+	request := httptest.NewRequest("POST", "/save", strings.NewReader("Age=abc&Name=joe"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	app.Handler().ServeHTTP(httptest.NewRecorder(), request)
+
+	binds, ok := err.(BindErrors)
+	if !ok || len(binds) != 1 || binds[0] != (BindError{Field: "Age", Value: "abc", Reason: "must be a whole number"}) {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+
+	if output.Name != "joe" {
+		t.Errorf("other fields not bound: %+v", output)
+	}
+}
+
+func TestBodyNilPointer(t *testing.T) {
+	type address struct {
+		City string
+	}
+
+	type form struct {
+		Address *address
+	}
+
+	var output form
+	var err error
+
+	app := MakeApp("en")
+	action := func(ctx *Context) string {
+		err = ctx.Body(&output)
+		return ""
+	}
+
+	app.Register("POST", "/save", &action)
+
+	request := httptest.NewRequest("POST", "/save", strings.NewReader("Address.City=x"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response := httptest.NewRecorder()
+	app.Handler().ServeHTTP(response, request)
+
+	if response.Code != 200 || err != nil || output.Address == nil || output.Address.City != "x" {
+		t.Fatalf("nested pointer not bound: %d %v %+v", response.Code, err, output.Address)
+	}
+}
+
+func TestBodyUnknownField(t *testing.T) {
+	type form struct {
+		Name string
+	}
+
+	var output form
+	var err error
+
+	app := MakeApp("en")
+	action := func(ctx *Context) string {
+		err = ctx.Body(&output)
+		return ""
+	}
+
+	app.Register("POST", "/save", &action)
+
+	reader, writer, _ := os.Pipe()
+	stdout := os.Stdout
+	os.Stdout = writer
+
+	request := httptest.NewRequest("POST", "/save", strings.NewReader("Name=joe&submit=Send&items.0=x"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	app.Handler().ServeHTTP(httptest.NewRecorder(), request)
+
+	os.Stdout = stdout
+	writer.Close()
+	printed, _ := io.ReadAll(reader)
+
+	if err != nil || output.Name != "joe" {
+		t.Fatalf("unexpected result: %v %+v", err, output)
+	}
+
+	if len(printed) > 0 {
+		t.Errorf("unknown fields printed: %q", printed)
+	}
+}
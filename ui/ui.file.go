@@ -0,0 +1,201 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+)
+
+// ErrTooLarge is returned by ctx.Body and ctx.Files when posted form or files exceed
+// App.Config.MaxBodyBytes or BodyLimit of the route.
+var ErrTooLarge = errors.New("file is too large")
+
+func (c *TInput) Accept(value string) *TInput {
+	c.file.accept = value
+	return c
+}
+
+func (c *TInput) Multiple(value ...bool) *TInput {
+	if value == nil {
+		c.file.multiple = true
+		return c
+	}
+
+	c.file.multiple = value[0]
+	return c
+}
+
+// MaxSize rejects bigger files in the browser with the message, e.g. ctx.Translate("File is too large"),
+// the server limits requests by App.Config.MaxBodyBytes or BodyLimit.
+func (c *TInput) MaxSize(bytes int64, message ...string) *TInput {
+	c.file.maxSize = bytes
+	c.file.message = "File is too large"
+
+	if len(message) > 0 {
+		c.file.message = message[0]
+	}
+
+	return c
+}
+
+// IFile is file input, forms with files are posted as multipart form with upload progress in the loading indicator.
+// Read files by ctx.Files or bind them by ctx.Body into []byte, *multipart.FileHeader or []*multipart.FileHeader fields.
+func IFile(name string, data ...any) *TInput {
+	c := &TInput{
+		as:      "file",
+		target:  Target(),
+		name:    name,
+		size:    MD,
+		visible: true,
+	}
+
+	if len(data) > 0 {
+		c.data = data[0]
+	}
+
+	c.Render = func(text string) string {
+		if !c.visible {
+			return ""
+		}
+
+		onchange := c.onchange
+
+		if c.file.maxSize > 0 {
+			message, _ := json.Marshal(c.file.message)
+
+			onchange = Normalize(fmt.Sprintf(`
+				if (Array.from(this.files).some(file => file.size > %d)) {
+					__message(%s, "bg-red-700 text-white");
+					this.value = "";
+					return;
+				}
+			`, c.file.maxSize, message)) + onchange
+		}
+
+		return Div(c.class)(
+			Label(&c.target).
+				Class(c.classLabel).
+				Required(c.required).
+				Render(text),
+
+			Input(
+				Classes(INPUT, c.size, c.classInput,
+					If(c.disabled, func() string { return DISABLED }),
+					If(c.error != nil, func() string { return "border-l-8 border-red-600" }),
+				),
+				Attr{
+					ID:       c.target.ID,
+					Name:     c.name,
+					Type:     c.as,
+					Accept:   c.file.accept,
+					Multiple: c.file.multiple,
+					OnChange: onchange,
+					OnClick:  c.onclick,
+					Trigger:  c.trigger,
+					Required: c.required,
+					Disabled: c.disabled,
+				},
+			),
+
+			errorReason(c.error),
+		)
+	}
+
+	return c
+}
+
+// Files returns files uploaded in the multipart form field.
+func (ctx *Context) Files(name string) ([]*multipart.FileHeader, error) {
+	ctx.limitBody()
+
+	if err := ctx.Request.ParseMultipartForm(maxMemory); err != nil {
+		return nil, tooLarge(err)
+	}
+
+	return ctx.Request.MultipartForm.File[name], nil
+}
+
+var (
+	fileHeader  = reflect.TypeOf(&multipart.FileHeader{})
+	fileHeaders = reflect.TypeOf([]*multipart.FileHeader{})
+	fileBytes   = reflect.TypeOf([]byte{})
+)
+
+func bindFiles(output any, files map[string][]*multipart.FileHeader) error {
+	for name, headers := range files {
+		if len(headers) == 0 {
+			continue
+		}
+
+		field, err := PathValue(output, name)
+		if err != nil || field == nil || !field.CanSet() {
+			continue
+		}
+
+		switch field.Type() {
+		case fileHeader:
+			field.Set(reflect.ValueOf(headers[0]))
+
+		case fileHeaders:
+			field.Set(reflect.ValueOf(headers))
+
+		case fileBytes:
+			file, err := headers[0].Open()
+			if err != nil {
+				return err
+			}
+
+			data, err := io.ReadAll(file)
+			file.Close()
+
+			if err != nil {
+				return err
+			}
+
+			field.SetBytes(data)
+		}
+	}
+
+	return nil
+}
+
+// BodyLimit is middleware changing App.Config.MaxBodyBytes for the route, e.g. for bigger uploads.
+func BodyLimit(bytes int64) Middleware {
+	return func(next Callable) Callable {
+		return func(ctx *Context) string {
+			ctx.limit = bytes
+			return next(ctx)
+		}
+	}
+}
+
+// limitBody wraps the request body once, before the form or files are read.
+func (ctx *Context) limitBody() {
+	if ctx.limited || ctx.Request.Body == nil {
+		return
+	}
+
+	ctx.limited = true
+
+	limit := ctx.limit
+	if limit == 0 && ctx.App != nil {
+		limit = ctx.App.Config.MaxBodyBytes
+	}
+
+	if limit > 0 {
+		ctx.Request.Body = http.MaxBytesReader(ctx.Response, ctx.Request.Body, limit)
+	}
+}
+
+func tooLarge(err error) error {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return ErrTooLarge
+	}
+
+	return err
+}
@@ -68,6 +68,8 @@ type Attr struct {
 	Placeholder  string
 	Autocomplete string
 	OnChange     string
+	Trigger      string
+	Accept       string
 	Max          string
 	Min          string
 	Target       string
@@ -78,6 +80,7 @@ type Attr struct {
 	Disabled     bool
 	Required     bool
 	Readonly     bool
+	Multiple     bool
 }
 
 type AOption struct {
@@ -148,6 +151,10 @@ func attributes(attrs ...Attr) string {
 			result = append(result, fmt.Sprintf(`onsubmit="%s"`, attr.OnSubmit))
 		}
 
+		if attr.Trigger != "" {
+			result = append(result, fmt.Sprintf(`data-srui-trigger="%s"`, attr.Trigger))
+		}
+
 		if attr.Value != "" {
 			result = append(result, fmt.Sprintf(`value="%s"`, attr.Value))
 		}
@@ -224,6 +231,14 @@ func attributes(attrs ...Attr) string {
 			result = append(result, `disabled="disabled"`)
 		}
 
+		if attr.Multiple {
+			result = append(result, `multiple="multiple"`)
+		}
+
+		if attr.Accept != "" {
+			result = append(result, fmt.Sprintf(`accept="%s"`, attr.Accept))
+		}
+
 		if attr.Readonly {
 			result = append(result, `readonly="readonly"`)
 		}
@@ -365,9 +380,11 @@ var Classes = func(values ...string) string {
 // }
 
 type Action struct {
-	Method *Callable
-	Target Attr
-	Values []any
+	Method    *Callable
+	Target    Attr
+	Values    []any
+	Indicator Indicator
+	Confirm   *Confirm
 	// Type   ActionType
 }
 
@@ -493,36 +510,72 @@ func ErrorForm(errs *error, translations *map[string]string) string {
 		return ""
 	}
 
-	temp := (*errs).(validator.ValidationErrors)
+	translate := func(text string) string {
+		if translations != nil && (*translations)[text] != "" {
+			return (*translations)[text]
+		}
 
-	// for _, err := range errs { fmt.Printf("%+v\n", err.Field()) }
+		return text
+	}
 
-	return Div("text-red-600 p-4 rounded text-center border-4 border-red-600 bg-white")(
-		Map(temp, func(err *validator.FieldError, _ int) string {
-			trans := (*err).Field()
-			invalid := "has invalid value"
+	row := func(field string, reason string) string {
+		return Div("")(
+			Span("font-bold uppercase")(translate(field)),
+			Space,
+			translate(reason),
+		)
+	}
 
-			if translations != nil && (*translations)[trans] != "" {
-				trans = (*translations)[trans]
-			}
+	var rows []string
 
-			if translations != nil && (*translations)[invalid] != "" {
-				invalid = (*translations)[invalid]
-			}
+	var binds BindErrors
+	if errors.As(*errs, &binds) {
+		for _, err := range binds {
+			rows = append(rows, row(err.Field, err.Reason))
+		}
+	}
 
-			return Div("")(
-				Span("font-bold uppercase")(trans),
-				Space,
-				invalid,
-			)
-		}),
-	)
+	var validations validator.ValidationErrors
+	if errors.As(*errs, &validations) {
+		// for _, err := range errs { fmt.Printf("%+v\n", err.Field()) }
+
+		for _, err := range validations {
+			rows = append(rows, row(err.Field(), "has invalid value"))
+		}
+	}
+
+	if len(rows) == 0 {
+		rows = append(rows, Div("")(translate((*errs).Error())))
+	}
+
+	return Div("text-red-600 p-4 rounded text-center border-4 border-red-600 bg-white")(rows...)
 }
 
 func Print(value any) string {
 	return fmt.Sprintf("%+v", value)
 }
 
+// structValue dereferences pointers to the struct, nil pointers are allocated when they can be set.
+func structValue(current reflect.Value, part string) (reflect.Value, error) {
+	for current.Kind() == reflect.Pointer {
+		if current.IsNil() {
+			if !current.CanSet() {
+				return current, fmt.Errorf("nil pointer at path segment: %s", part)
+			}
+
+			current.Set(reflect.New(current.Type().Elem()))
+		}
+
+		current = current.Elem()
+	}
+
+	if current.Kind() != reflect.Struct {
+		return current, fmt.Errorf("invalid path segment: %s", part)
+	}
+
+	return current, nil
+}
+
 func PathValue(obj any, path string) (*reflect.Value, error) {
 	parts := strings.Split(path, ".")
 	current := reflect.ValueOf(obj)
@@ -534,16 +587,19 @@ func PathValue(obj any, path string) (*reflect.Value, error) {
 			indexStr := part[strings.Index(part, "[")+1 : strings.Index(part, "]")]
 			indexVal, err := strconv.Atoi(indexStr)
 			if err != nil {
-				fmt.Println(err)
 				return nil, err
 			}
 
-			current = current.Elem().FieldByName(fieldName)
-			if !current.IsValid() || current.Kind() != reflect.Slice {
-				fmt.Printf("invalid slice field: %s", fieldName)
+			current, err = structValue(current, part)
+			if err != nil {
 				return nil, err
 			}
 
+			current = current.FieldByName(fieldName)
+			if !current.IsValid() || current.Kind() != reflect.Slice {
+				return nil, fmt.Errorf("invalid slice field: %s", fieldName)
+			}
+
 			for current.Len() <= indexVal {
 				elemType := current.Type().Elem()
 
@@ -560,19 +616,18 @@ func PathValue(obj any, path string) (*reflect.Value, error) {
 
 			// fmt.Printf("current: %v\n", current)
 		} else {
-			if current.Kind() == reflect.Pointer {
-				current = current.Elem()
+			var err error
+
+			current, err = structValue(current, part)
+			if err != nil {
+				return nil, err
 			}
 
 			current = current.FieldByName(part)
 		}
 
 		if !current.IsValid() {
-			err := fmt.Errorf("invalid path segment: %s", part)
-
-			fmt.Println(err)
-
-			return nil, err
+			return nil, fmt.Errorf("invalid path segment: %s", part)
 		}
 	}
 
@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"strings"
 	"time"
-
-	"github.com/go-playground/validator/v10"
 )
 
 type TInput struct {
@@ -23,18 +21,25 @@ type TInput struct {
 	size         string
 	onclick      string
 	onchange     string
+	trigger      string
 	as           string
 	name         string
 	pattern      string
 	value        string
 	valueFormat  string
-	error        validator.FieldError
+	error        error
 	target       Attr
 	numbers      struct {
 		Min  float64
 		Max  float64
 		Step float64
 	}
+	file struct {
+		accept   string
+		multiple bool
+		maxSize  int64
+		message  string
+	}
 	visible  bool
 	required bool
 	disabled bool
@@ -112,16 +117,8 @@ func (c *TInput) Required(value ...bool) *TInput {
 }
 
 func (c *TInput) Error(errs *error) *TInput {
-	if errs == nil || *errs == nil {
-		return c
-	}
-
-	temp := (*errs).(validator.ValidationErrors)
-
-	for _, err := range temp {
-		if err.Field() == c.name {
-			c.error = err
-		}
+	if err := fieldError(errs, c.name); err != nil {
+		c.error = err
 	}
 
 	return c
@@ -167,6 +164,13 @@ func (c *TInput) Click(action string) *TInput {
 	return c
 }
 
+// Trigger sets action fired by the client runtime, e.g. ctx.Call(search).Trigger("input", Debounce(300*time.Millisecond)).Render(target).
+func (c *TInput) Trigger(action string) *TInput {
+	c.trigger = action
+
+	return c
+}
+
 func (c *TInput) Numbers(min float64, max float64, step float64) *TInput {
 	c.numbers.Min = min
 	c.numbers.Max = max
@@ -241,6 +245,7 @@ func IText(name string, data ...any) *TInput {
 					Type:         c.as,
 					OnChange:     c.onchange,
 					OnClick:      c.onclick,
+					Trigger:      c.trigger,
 					Required:     c.required,
 					Disabled:     c.disabled,
 					Value:        value,
@@ -250,7 +255,7 @@ func IText(name string, data ...any) *TInput {
 				},
 			),
 
-			// ErrorField(c.error),
+			errorReason(c.error),
 		)
 	}
 
@@ -337,12 +342,15 @@ func IArea(name string, data ...any) *TInput {
 					ID:          c.target.ID,
 					Name:        c.name,
 					OnClick:     c.onclick,
+					Trigger:     c.trigger,
 					Required:    c.required,
 					Disabled:    c.disabled,
 					Readonly:    c.readonly,
 					Placeholder: c.placeholder,
 				},
 			)(value),
+
+			errorReason(c.error),
 		)
 	}
 
@@ -403,11 +411,14 @@ func IPassword(name string, data ...any) *TInput {
 					ID:          c.target.ID,
 					Name:        c.name,
 					OnClick:     c.onclick,
+					Trigger:     c.trigger,
 					Required:    c.required,
 					Disabled:    c.disabled,
 					Placeholder: c.placeholder,
 				},
 			),
+
+			errorReason(c.error),
 		)
 	}
 
@@ -516,12 +527,15 @@ func IDate(name string, data ...any) *TInput {
 					ID:          c.target.ID,
 					Name:        c.name,
 					OnClick:     c.onclick,
+					Trigger:     c.trigger,
 					OnChange:    onChangeWithValidation,
 					Required:    c.required,
 					Disabled:    c.disabled,
 					Placeholder: c.placeholder,
 				},
 			),
+
+			errorReason(c.error),
 		)
 	}
 	return c
@@ -593,11 +607,14 @@ func ITime(name string, data ...any) *TInput {
 					ID:          c.target.ID,
 					Name:        c.name,
 					OnClick:     c.onclick,
+					Trigger:     c.trigger,
 					Required:    c.required,
 					Disabled:    c.disabled,
 					Placeholder: c.placeholder,
 				},
 			),
+
+			errorReason(c.error),
 		)
 	}
 	return c
@@ -669,11 +686,14 @@ func IDateTime(name string, data ...any) *TInput {
 					ID:          c.target.ID,
 					Name:        c.name,
 					OnClick:     c.onclick,
+					Trigger:     c.trigger,
 					Required:    c.required,
 					Disabled:    c.disabled,
 					Placeholder: c.placeholder,
 				},
 			),
+
+			errorReason(c.error),
 		)
 	}
 	return c
@@ -752,12 +772,15 @@ func INumber(name string, data ...any) *TInput {
 					ID:          c.target.ID,
 					Name:        c.name,
 					OnClick:     c.onclick,
+					Trigger:     c.trigger,
 					Required:    c.required,
 					Disabled:    c.disabled,
 					Placeholder: c.placeholder,
 				},
 			),
 
+			errorReason(c.error),
+
 			// Script(fmt.Sprintf(`
 			// 	(function() {
 			// 		const input = document.getElementById('%v');
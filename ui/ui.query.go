@@ -185,7 +185,7 @@ func (collate *TCollate[T]) onXLS(ctx *Context) string {
 	}
 
 	query.Limit = 1000000
-	result := collate.Load(query)
+	result := collate.LoadContext(ctx.Ctx(), query)
 
 	var filename string
 	var reader io.Reader
@@ -374,6 +374,11 @@ func endOfDay(t time.Time) time.Time {
 }
 
 func (c *TCollate[T]) Load(query *TQuery) *TCollateResult[T] {
+	return c.LoadContext(context.Background(), query)
+}
+
+// LoadContext loads data like Load, queries are cancelled when ctx is done, e.g. user navigates away
+func (c *TCollate[T]) LoadContext(ctx context.Context, query *TQuery) *TCollateResult[T] {
 	result := &TCollateResult[T]{
 		Total:    0,
 		Filtered: 0,
@@ -381,9 +386,10 @@ func (c *TCollate[T]) Load(query *TQuery) *TCollateResult[T] {
 		Query:    query,
 	}
 
-	c.Database.Model(result.Data).Count(&result.Total)
+	database := c.Database.WithContext(ctx)
+	database.Model(result.Data).Count(&result.Total)
 
-	temp := c.Database.Model(&result.Data).
+	temp := database.Model(&result.Data).
 		Session(&gorm.Session{}).
 		Order(query.Order).
 		Limit(int(query.Limit)).
@@ -478,7 +484,7 @@ func (c *TCollate[T]) Load(query *TQuery) *TCollateResult[T] {
 }
 
 func (collate *TCollate[T]) Render(ctx *Context, query *TQuery) string {
-	result := collate.Load(query)
+	result := collate.LoadContext(ctx.Ctx(), query)
 
 	return Div("flex flex-col gap-2 mt-2", collate.Target)(
 		Div("flex flex-col")(
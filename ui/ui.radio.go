@@ -3,8 +3,6 @@ package ui
 import (
 	"fmt"
 	"strings"
-
-	"github.com/go-playground/validator/v10"
 )
 
 func IRadio(name string, data ...any) *TInput {
@@ -98,7 +96,7 @@ func IRadio(name string, data ...any) *TInput {
 }
 
 type ARadio struct {
-	error          validator.FieldError
+	error          error
 	data           any
 	name           string
 	class          string
@@ -118,16 +116,8 @@ type ARadio struct {
 }
 
 func (c *ARadio) Error(errs *error) *ARadio {
-	if errs == nil {
-		return c
-	}
-
-	temp := (*errs).(validator.ValidationErrors)
-
-	for _, err := range temp {
-		if err.Field() == c.name {
-			c.error = err
-		}
+	if err := fieldError(errs, c.name); err != nil {
+		c.error = err
 	}
 
 	return c
@@ -306,6 +296,8 @@ func (c *ARadio) Render(text string) string {
 			// 	)
 			// }),
 		),
+
+		errorReason(c.error),
 	)
 }
 
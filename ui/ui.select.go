@@ -3,14 +3,12 @@ package ui
 import (
 	"fmt"
 	"strings"
-
-	"github.com/go-playground/validator/v10"
 )
 
 type ASelect struct {
 	as          string
 	data        any
-	error       validator.FieldError
+	error       error
 	name        string
 	class       string
 	size        string
@@ -25,16 +23,8 @@ type ASelect struct {
 }
 
 func (c *ASelect) Error(errs *error) *ASelect {
-	if errs == nil {
-		return c
-	}
-
-	temp := (*errs).(validator.ValidationErrors)
-
-	for _, err := range temp {
-		if err.Field() == c.name {
-			c.error = err
-		}
+	if err := fieldError(errs, c.name); err != nil {
+		c.error = err
 	}
 
 	return c
@@ -141,6 +131,8 @@ func (c *ASelect) Render(text string) string {
 				return Option("", Attr{Value: option.ID, Selected: If(option.ID == value, func() string { return "selected" })})(option.Value)
 			}),
 		),
+
+		errorReason(c.error),
 	)
 }
 
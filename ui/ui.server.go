@@ -1,23 +1,34 @@
 package ui
 
 import (
+	"context"
+	"crypto/tls"
 	"embed"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"log"
 	"math/rand"
+	"mime"
 	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
 	"reflect"
 	"regexp"
 	"runtime"
+	"runtime/debug"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
+	"github.com/go-playground/validator/v10"
 	"golang.org/x/net/websocket"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
@@ -25,12 +36,14 @@ import (
 
 type Callable = func(*Context) string
 
+// Middleware wraps a Callable, it can run code around next or short-circuit by not calling it.
+type Middleware = func(next Callable) Callable
+
 var (
 	eventPath      = "/"
-	mu             sync.Mutex
-	stored         = make(map[*Callable]string)
 	reReplaceChars = regexp.MustCompile(`[./:-]`)
 	reRemoveChars  = regexp.MustCompile(`[*()\[\]]`)
+	reDashes       = regexp.MustCompile(`-{2,}`)
 )
 
 type BodyItem struct {
@@ -59,9 +72,37 @@ type Swap string
 const (
 	OUTLINE Swap = "outline"
 	INLINE  Swap = "inline"
+	APPEND  Swap = "append"
+	PREPEND Swap = "prepend"
+	BEFORE  Swap = "before"
+	AFTER   Swap = "after"
+	REMOVE  Swap = "remove"
+	MORPH   Swap = "morph"
 	NONE    Swap = "none"
 )
 
+// Indicator tells the client what to show while an action is loading.
+type Indicator string
+
+const (
+	INDICATOR_OVERLAY Indicator = "overlay"
+	INDICATOR_SPINNER Indicator = "spinner"
+	INDICATOR_DISABLE Indicator = "disable"
+	INDICATOR_NONE    Indicator = "none"
+)
+
+// IndicatorOf shows element with the target id while loading, the element should be hidden by the "hidden" class.
+func IndicatorOf(target Attr) Indicator {
+	return Indicator("#" + target.ID)
+}
+
+// Confirm asks user to confirm the action in a modal before it is posted. Empty labels default to "OK" and "Cancel".
+type Confirm struct {
+	Message string
+	OK      string
+	Cancel  string
+}
+
 type ActionType string
 
 const (
@@ -75,6 +116,9 @@ type Context struct {
 	Response  http.ResponseWriter
 	SessionID string
 	append    []string
+	patches   []string
+	limit     int64
+	limited   bool
 }
 
 type TSession struct {
@@ -119,6 +163,36 @@ func (session *TSession) Save(output any) {
 	session.DB.Where("session_id = ? and name = ?", session.SessionID, session.Name).Save(temp)
 }
 
+// PathValue returns the value of named wildcard from the route pattern, e.g. "id" for "/orders/{id}".
+func (ctx *Context) PathValue(name string) string {
+	return ctx.Request.PathValue(name)
+}
+
+// JSON marshals value as response body with application/json content type, useful for PUT, PATCH or DELETE routes.
+func (ctx *Context) JSON(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Println(err)
+		ctx.Response.WriteHeader(http.StatusInternalServerError)
+		return ""
+	}
+
+	ctx.Response.Header().Set("Content-Type", "application/json")
+
+	return string(data)
+}
+
+// Partial reports whether the request was made by an action (__post or __submit) and its result is swapped into a target.
+func (ctx *Context) Partial() bool {
+	return ctx.Request.Header.Get("X-SRUI-Request") == "true"
+}
+
+// Ctx returns context of the request, it is cancelled when the client goes away or the action times out.
+// Pass it to database calls, e.g. db.WithContext(ctx.Ctx()).
+func (ctx *Context) Ctx() context.Context {
+	return ctx.Request.Context()
+}
+
 func (ctx *Context) IP() string {
 	return ctx.Request.RemoteAddr
 }
@@ -131,158 +205,115 @@ func (ctx *Context) Session(db *gorm.DB, name string) *TSession {
 	}
 }
 
+// Body binds data posted by actions (__post, __submit), url-encoded or multipart forms into output,
+// form fields are named by struct paths, e.g. Filter[0].Dates.From.
+// Values which can not be converted are returned as BindErrors, the other fields are still bound.
 func (ctx *Context) Body(output any) error {
-	body, err := io.ReadAll(ctx.Request.Body)
+	data, err := ctx.items()
 	if err != nil {
 		return err
 	}
 
-	var data []BodyItem
-	if len(body) > 0 {
-		err = json.Unmarshal(body, &data)
+	// server only fields are set only by values resolved from the token
+	data = clientItems(output, data)
+
+	if ctx.App != nil {
+		data, err = ctx.App.unseal(ctx.Request.URL.Path, ctx.SessionID, data)
 		if err != nil {
 			return err
 		}
 	}
 
+	var errs BindErrors
+
 	for _, item := range data {
-		structFieldValue, err := PathValue(output, item.Name)
-		if err != nil {
-			fmt.Println("Error getting field", item.Name, err)
+		// fields without struct field, e.g. name of the submit button, are skipped
+		field, err := PathValue(output, item.Name)
+		if err != nil || field == nil {
 			continue
 		}
 
-		if !structFieldValue.CanSet() {
+		if !field.CanSet() {
 			continue
 		}
 
-		val := reflect.ValueOf(item.Value)
+		if err := bindValue(*field, item.Value); err != nil {
+			errs = append(errs, BindError{Field: item.Name, Value: item.Value, Reason: err.Error()})
+		}
+	}
 
-		if structFieldValue.Type() != val.Type() {
-			switch item.Type {
-			case "date":
-				t, err := time.Parse("2006-01-02", item.Value)
-				if err != nil {
-					fmt.Println("Error parsing date", err)
-					continue
-				}
-				if structFieldValue.Type() == reflect.TypeOf(gorm.DeletedAt{}) {
-					val = reflect.ValueOf(gorm.DeletedAt{Time: t, Valid: true})
-				} else {
-					val = reflect.ValueOf(t)
-				}
+	if ctx.Request.MultipartForm != nil {
+		if err := bindFiles(output, ctx.Request.MultipartForm.File); err != nil {
+			return err
+		}
+	}
 
-			case "bool", "checkbox":
-				val = reflect.ValueOf(item.Value == "true")
+	if len(errs) > 0 {
+		return errs
+	}
 
-			case "radio", "string":
-				val = reflect.ValueOf(item.Value)
+	return nil
+}
 
-			case "time":
-				t, err := time.Parse("15:04", item.Value)
-				if err != nil {
-					fmt.Println("Error parsing time", err)
-					continue
-				}
-				val = reflect.ValueOf(t)
+// maxMemory is the part of multipart form kept in memory, the rest is stored in temporary files.
+const maxMemory = 32 << 20
 
-			case "Time":
-				t, err := time.Parse("2006-01-02 15:04:05 -0700 UTC", item.Value)
-				if err != nil {
-					fmt.Println("Error parsing time", err)
-				}
-				val = reflect.ValueOf(t)
-
-			case "uint":
-				cleanedValue := strings.ReplaceAll(item.Value, "_", "")
-				n, err := strconv.ParseUint(cleanedValue, 10, 64)
-				if err != nil {
-					fmt.Println("Error parsing number", err)
-					continue
-				}
-				val = reflect.ValueOf(uint(n))
-
-			case "int":
-				cleanedValue := strings.ReplaceAll(item.Value, "_", "")
-				n, err := strconv.ParseInt(cleanedValue, 10, 64)
-				if err != nil {
-					fmt.Println("Error parsing number", err)
-					continue
-				}
-				val = reflect.ValueOf(int(n))
-
-			case "int64":
-				cleanedValue := strings.ReplaceAll(item.Value, "_", "")
-				n, err := strconv.ParseInt(cleanedValue, 10, 64)
-				if err != nil {
-					fmt.Println("Error parsing number", err)
-					continue
-				}
-				val = reflect.ValueOf(int64(n))
-
-			case "number":
-				cleanedValue := strings.ReplaceAll(item.Value, "_", "")
-				n, err := strconv.Atoi(cleanedValue)
-				if err != nil {
-					fmt.Println("Error parsing number", err)
-					continue
-				}
-				val = reflect.ValueOf(n)
-
-			case "decimal":
-				cleanedValue := strings.ReplaceAll(item.Value, "_", "")
-				f, err := strconv.ParseFloat(cleanedValue, 64)
-				if err != nil {
-					fmt.Println("Error parsing decimal", err)
-					continue
-				}
-				val = reflect.ValueOf(f)
-
-			case "float64":
-				cleanedValue := strings.ReplaceAll(item.Value, "_", "")
-				f, err := strconv.ParseFloat(cleanedValue, 64)
-				if err != nil {
-					fmt.Println("Error parsing float64", err)
-					continue
-				}
-				val = reflect.ValueOf(f)
+func (ctx *Context) items() ([]BodyItem, error) {
+	mediaType, _, _ := mime.ParseMediaType(ctx.Request.Header.Get("Content-Type"))
 
-			case "datetime-local":
-				t, err := time.Parse("2006-01-02T15:04", item.Value)
-				if err != nil {
-					fmt.Println("Error parsing datetime-local", err)
-					continue
-				}
-				val = reflect.ValueOf(t)
+	ctx.limitBody()
 
-			// case "text":
-			// 	val = reflect.ValueOf(item.Value)
+	switch mediaType {
+	case "multipart/form-data":
+		if err := ctx.Request.ParseMultipartForm(maxMemory); err != nil {
+			return nil, tooLarge(err)
+		}
 
-			case "":
-				continue
+		return formItems(ctx.Request.MultipartForm.Value), nil
 
-			case "Model": // gorm.Model
-				continue
+	case "application/x-www-form-urlencoded":
+		if err := ctx.Request.ParseForm(); err != nil {
+			return nil, tooLarge(err)
+		}
 
-			default:
-				fmt.Println("Skipping (name;type;value):", item.Name, ";", item.Type, ";", item.Value)
-				continue
-			}
+		return formItems(ctx.Request.PostForm), nil
+	}
+
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return nil, tooLarge(err)
+	}
+
+	var data []BodyItem
+	if len(body) > 0 {
+		err = json.Unmarshal(body, &data)
+		if err != nil {
+			return nil, err
 		}
+	}
+
+	return data, nil
+}
 
-		// fmt.Println("Setting", item.Name, "to", item.Value)
-		structFieldValue.Set(val)
+// formItems converts form values into body items, values are converted by the type of the bound field.
+func formItems(values map[string][]string) []BodyItem {
+	var data []BodyItem
+
+	for name, list := range values {
+		for _, value := range list {
+			data = append(data, BodyItem{Name: name, Value: value})
+		}
 	}
 
-	return nil
+	return data
 }
 
-func (ctx *Context) Action(uid string, action Callable) **Callable {
+func (ctx *Context) Action(uid string, action Callable, middleware ...Middleware) **Callable {
 	if ctx.App == nil {
 		panic("App is nil, cannot register component. Did you set the App field in Context?")
 	}
 
-	return ctx.App.Action(uid, action)
+	return ctx.App.Action(uid, action, middleware...)
 }
 
 func (ctx *Context) Callable(action Callable) **Callable {
@@ -294,16 +325,15 @@ func (ctx *Context) Callable(action Callable) **Callable {
 }
 
 func (ctx *Context) Post(as ActionType, swap Swap, action *Action) string {
-	path, ok := stored[action.Method]
-
-	if !ok {
-		funcName := reflect.ValueOf(*action.Method).String()
-		panic(fmt.Sprintf("Function '%s' probably not registered. Cannot make call to this function.", funcName))
-	}
+	return Normalize(ctx.call(as, swap, action))
+}
 
+// values converts fields of structs passed to the action into items sent by the client, see App.seal.
+func (ctx *Context) values(path string, values []any) []BodyItem {
 	var body []BodyItem
+	var server []BodyItem
 
-	for _, item := range action.Values {
+	for _, item := range values {
 		v := reflect.ValueOf(item)
 
 		if v.Kind() == reflect.Pointer {
@@ -311,26 +341,51 @@ func (ctx *Context) Post(as ActionType, swap Swap, action *Action) string {
 		}
 
 		for i := range v.NumField() {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+
 			field := v.Field(i)
 			fieldName := v.Type().Field(i).Name
 			fieldType := field.Type().Name()
-			fieldValue := field.Interface()
 
-			// Handle time.Time specially to avoid parsing issues
-			if field.Type().String() == "time.Time" {
-				if t, ok := fieldValue.(time.Time); ok {
-					fieldValue = t.Format("2006-01-02 15:04:05 -0700 UTC")
-				}
+			fieldValue, ok := formatValue(field)
+			if !ok {
+				continue
 			}
 
-			body = append(body, BodyItem{
+			item := BodyItem{
 				Name:  fieldName,
 				Type:  fieldType,
-				Value: fmt.Sprintf("%v", fieldValue),
-			})
+				Value: fieldValue,
+			}
+
+			// server only fields never reach the client
+			if v.Type().Field(i).Tag.Get("srui") == "server" {
+				server = append(server, item)
+			} else {
+				body = append(body, item)
+			}
 		}
 	}
 
+	return ctx.App.seal(path, ctx.SessionID, body, server)
+}
+
+func (ctx *Context) call(as ActionType, swap Swap, action *Action) string {
+	if ctx.App == nil {
+		panic("App is nil, cannot make call. Did you set the App field in Context?")
+	}
+
+	path, ok := ctx.App.path(action.Method)
+
+	if !ok {
+		funcName := reflect.ValueOf(*action.Method).String()
+		panic(fmt.Sprintf("Function '%s' probably not registered. Cannot make call to this function.", funcName))
+	}
+
+	body := ctx.values(path, action.Values)
+
 	values := "[]"
 
 	if len(body) > 0 {
@@ -341,25 +396,125 @@ func (ctx *Context) Post(as ActionType, swap Swap, action *Action) string {
 		}
 	}
 
+	path = ctx.App.URL(path)
+
+	options := map[string]any{}
+
+	if action.Indicator != "" {
+		options["indicator"] = action.Indicator
+	}
+
+	if action.Confirm != nil {
+		options["confirm"] = ctx.confirm(action.Confirm)
+	}
+
+	if len(options) > 0 {
+		temp, err := json.Marshal(options)
+
+		if err == nil {
+			values += ", " + string(temp)
+		}
+	}
+
 	if as == FORM {
-		return Normalize(fmt.Sprintf(`__submit(event, "%s", "%s", "%s", %s) `, swap, action.Target.ID, path, values))
+		return fmt.Sprintf(`__submit(event, "%s", "%s", "%s", %s) `, swap, action.Target.ID, path, values)
+	}
+
+	return fmt.Sprintf(`__post(event, "%s", "%s", "%s", %s) `, swap, action.Target.ID, path, values)
+}
+
+// confirm returns translated texts of the modal, markup is rendered once per page by confirmModal.
+func (ctx *Context) confirm(confirm *Confirm) map[string]string {
+	ok := confirm.OK
+	if ok == "" {
+		ok = "OK"
+	}
+
+	cancel := confirm.Cancel
+	if cancel == "" {
+		cancel = "Cancel"
 	}
 
-	return Normalize(fmt.Sprintf(`__post(event, "%s", "%s", "%s", %s) `, swap, action.Target.ID, path, values))
+	return map[string]string{
+		"message": ctx.Translate(confirm.Message),
+		"ok":      ctx.Translate(ok),
+		"cancel":  ctx.Translate(cancel),
+	}
+}
+
+// confirmModal is markup of the confirmation modal, __confirm fills in the message and labels.
+func confirmModal() string {
+	return Div("fixed inset-0 z-50 flex items-center justify-center bg-gray-800 bg-opacity-50")(
+		Form("bg-white rounded-lg shadow-xl border border-gray-300 p-6 m-4 w-full max-w-sm")(
+			Div("text-lg mb-6")(),
+			Div("flex justify-end gap-4")(
+				Button().Reset().Color(GrayOutline).Class("rounded").Render(""),
+				Button().Submit().Color(Red).Class("rounded").Render(""),
+			),
+		),
+	)
 }
 
 type Actions struct {
 	Render  func(target Attr) string
 	Replace func(target Attr) string
+	Append  func(target Attr) string
+	Prepend func(target Attr) string
+	Before  func(target Attr) string
+	After   func(target Attr) string
+	Remove  func(target Attr) string
+	Morph   func(target Attr) string
 	None    func() string
-	// AsSubmit func(target Attr, swap ...Swap) Attr
-	// AsClick  func(target Attr, swap ...Swap) Attr
+
+	// Trigger, Every and OnVisible make the action fired by the client runtime,
+	// the rendered value is meant for Attr.Trigger or TInput.Trigger.
+	Trigger   func(event string, options ...TriggerOption) Actions
+	Every     func(interval time.Duration) Actions
+	OnVisible func() Actions
+
+	Indicator func(indicator Indicator) Actions
+	// Confirm shows modal before posting, labels are texts of OK and Cancel buttons.
+	Confirm func(message string, labels ...string) Actions
+}
+
+// TriggerOption limits how often triggered action is fired.
+type TriggerOption struct {
+	Debounce time.Duration
+	Throttle time.Duration
+}
+
+// Debounce fires the action once events stop for the wait duration.
+func Debounce(wait time.Duration) TriggerOption {
+	return TriggerOption{Debounce: wait}
+}
+
+// Throttle fires the action at most once per wait duration.
+func Throttle(wait time.Duration) TriggerOption {
+	return TriggerOption{Throttle: wait}
+}
+
+type trigger struct {
+	Event    string `json:"event,omitempty"`
+	Debounce int64  `json:"debounce,omitempty"`
+	Throttle int64  `json:"throttle,omitempty"`
+	Every    int64  `json:"every,omitempty"`
+	Visible  bool   `json:"visible,omitempty"`
+	Action   string `json:"action"`
 }
 
 type Submits struct {
 	Render  func(target Attr) Attr
 	Replace func(target Attr) Attr
+	Append  func(target Attr) Attr
+	Prepend func(target Attr) Attr
+	Before  func(target Attr) Attr
+	After   func(target Attr) Attr
+	Remove  func(target Attr) Attr
+	Morph   func(target Attr) Attr
 	None    func() Attr
+
+	Indicator func(indicator Indicator) Submits
+	Confirm   func(message string, labels ...string) Submits
 }
 
 // func swapize(swap ...Swap) Swap {
@@ -369,130 +524,230 @@ type Submits struct {
 // 	return INLINE
 // }
 
-func (ctx *Context) Submit(method Callable, values ...any) Submits {
-	callable := ctx.Callable(method)
+func makeActions(ctx *Context, as ActionType, callable **Callable, base Action, on *trigger) Actions {
+	post := func(swap Swap, target Attr) string {
+		action := base
+		action.Method = *callable
+		action.Target = target
 
-	return Submits{
-		Render: func(target Attr) Attr {
-			return Attr{OnSubmit: ctx.Post(FORM, INLINE, &Action{Method: *callable, Target: target, Values: values})}
+		if on == nil {
+			return ctx.Post(as, swap, &action)
+		}
+
+		spec := *on
+		spec.Action = ctx.call(as, swap, &action)
+		data, _ := json.Marshal(spec)
+
+		return Normalize(string(data))
+	}
+
+	with := func(change func(*trigger)) Actions {
+		temp := trigger{}
+		if on != nil {
+			temp = *on
+		}
+
+		change(&temp)
+
+		return makeActions(ctx, as, callable, base, &temp)
+	}
+
+	return Actions{
+		Render:  func(target Attr) string { return post(INLINE, target) },
+		Replace: func(target Attr) string { return post(OUTLINE, target) },
+		Append:  func(target Attr) string { return post(APPEND, target) },
+		Prepend: func(target Attr) string { return post(PREPEND, target) },
+		Before:  func(target Attr) string { return post(BEFORE, target) },
+		After:   func(target Attr) string { return post(AFTER, target) },
+		Remove:  func(target Attr) string { return post(REMOVE, target) },
+		Morph:   func(target Attr) string { return post(MORPH, target) },
+		None:    func() string { return post(NONE, Attr{}) },
+
+		Trigger: func(event string, options ...TriggerOption) Actions {
+			return with(func(t *trigger) {
+				t.Event = event
+
+				for _, option := range options {
+					if option.Debounce > 0 {
+						t.Debounce = option.Debounce.Milliseconds()
+					}
+
+					if option.Throttle > 0 {
+						t.Throttle = option.Throttle.Milliseconds()
+					}
+				}
+			})
 		},
-		Replace: func(target Attr) Attr {
-			return Attr{OnSubmit: ctx.Post(FORM, OUTLINE, &Action{Method: *callable, Target: target, Values: values})}
+		Every: func(interval time.Duration) Actions {
+			return with(func(t *trigger) { t.Every = interval.Milliseconds() })
 		},
-		None: func() Attr {
-			return Attr{OnSubmit: ctx.Post(FORM, NONE, &Action{Method: *callable, Values: values})}
+		OnVisible: func() Actions {
+			return with(func(t *trigger) { t.Visible = true })
 		},
-	}
-}
 
-func (ctx *Context) Click(method Callable, values ...any) Submits {
-	callable := ctx.Callable(method)
+		Indicator: func(indicator Indicator) Actions {
+			temp := base
+			temp.Indicator = indicator
 
-	return Submits{
-		Render: func(target Attr) Attr {
-			return Attr{OnClick: ctx.Post(POST, INLINE, &Action{Method: *callable, Target: target, Values: values})}
-		},
-		Replace: func(target Attr) Attr {
-			return Attr{OnClick: ctx.Post(POST, OUTLINE, &Action{Method: *callable, Target: target, Values: values})}
+			return makeActions(ctx, as, callable, temp, on)
 		},
-		None: func() Attr {
-			return Attr{OnClick: ctx.Post(POST, NONE, &Action{Method: *callable, Values: values})}
+
+		Confirm: func(message string, labels ...string) Actions {
+			confirm := &Confirm{Message: message}
+
+			if len(labels) > 0 {
+				confirm.OK = labels[0]
+			}
+
+			if len(labels) > 1 {
+				confirm.Cancel = labels[1]
+			}
+
+			temp := base
+			temp.Confirm = confirm
+
+			return makeActions(ctx, as, callable, temp, on)
 		},
 	}
 }
 
-func (ctx *Context) Send(method Callable, values ...any) Actions {
-	callable := ctx.Callable(method)
-
-	return Actions{
-		Render: func(target Attr) string {
-			return ctx.Post(FORM, INLINE, &Action{Method: *callable, Target: target, Values: values})
-		},
-		Replace: func(target Attr) string {
-			return ctx.Post(FORM, OUTLINE, &Action{Method: *callable, Target: target, Values: values})
+func makeSubmits(actions Actions, attr func(js string) Attr) Submits {
+	return Submits{
+		Render:  func(target Attr) Attr { return attr(actions.Render(target)) },
+		Replace: func(target Attr) Attr { return attr(actions.Replace(target)) },
+		Append:  func(target Attr) Attr { return attr(actions.Append(target)) },
+		Prepend: func(target Attr) Attr { return attr(actions.Prepend(target)) },
+		Before:  func(target Attr) Attr { return attr(actions.Before(target)) },
+		After:   func(target Attr) Attr { return attr(actions.After(target)) },
+		Remove:  func(target Attr) Attr { return attr(actions.Remove(target)) },
+		Morph:   func(target Attr) Attr { return attr(actions.Morph(target)) },
+		None:    func() Attr { return attr(actions.None()) },
+
+		Indicator: func(indicator Indicator) Submits {
+			return makeSubmits(actions.Indicator(indicator), attr)
 		},
-		None: func() string {
-			return ctx.Post(FORM, NONE, &Action{Method: *callable, Values: values})
+		Confirm: func(message string, labels ...string) Submits {
+			return makeSubmits(actions.Confirm(message, labels...), attr)
 		},
-		// AsSubmit: func(target Attr, swap ...Swap) Attr {
-		// 	return Attr{OnSubmit: ctx.Post(FORM, swapize(swap...), &Action{Method: *method, Target: target, Values: values})}
-		// },
-		// AsClick: func(target Attr, swap ...Swap) Attr {
-		// 	return Attr{OnClick: ctx.Post(FORM, swapize(swap...), &Action{Method: *method, Target: target, Values: values})}
-		// },
 	}
 }
 
+func (ctx *Context) Submit(method Callable, values ...any) Submits {
+	return makeSubmits(makeActions(ctx, FORM, ctx.Callable(method), Action{Values: values}, nil), func(js string) Attr {
+		return Attr{OnSubmit: js}
+	})
+}
+
+func (ctx *Context) Click(method Callable, values ...any) Submits {
+	return makeSubmits(makeActions(ctx, POST, ctx.Callable(method), Action{Values: values}, nil), func(js string) Attr {
+		return Attr{OnClick: js}
+	})
+}
+
+func (ctx *Context) Send(method Callable, values ...any) Actions {
+	return makeActions(ctx, FORM, ctx.Callable(method), Action{Values: values}, nil)
+}
+
 func (ctx *Context) Call(method Callable, values ...any) Actions {
-	callable := ctx.Callable(method)
+	return makeActions(ctx, POST, ctx.Callable(method), Action{Values: values}, nil)
+}
 
-	return Actions{
-		Render: func(target Attr) string {
-			return ctx.Post(POST, INLINE, &Action{Method: *callable, Target: target, Values: values})
-		},
-		Replace: func(target Attr) string {
-			return ctx.Post(POST, OUTLINE, &Action{Method: *callable, Target: target, Values: values})
-		},
-		None: func() string {
-			return ctx.Post(POST, NONE, &Action{Method: *callable, Values: values})
-		},
-		// AsSubmit: func(target Attr, swap ...Swap) Attr {
-		// 	return Attr{OnSubmit: ctx.Post(POST, swapize(swap...), &Action{Method: *method, Target: target, Values: values})}
-		// },
-		// AsClick: func(target Attr, swap ...Swap) Attr {
-		// 	return Attr{OnClick: ctx.Post(POST, swapize(swap...), &Action{Method: *method, Target: target, Values: values})}
-		// },
+// Load navigates to href without full page reload, content of the target (ContentID by default) is replaced
+// with the same element of the loaded page and the address is pushed to the browser history.
+func (ctx *Context) Load(href string, target ...Attr) Attr {
+	if ctx.App != nil && strings.HasPrefix(href, "/") {
+		href = ctx.App.URL(href)
+	}
+
+	if len(target) > 0 {
+		return Attr{OnClick: Normalize(fmt.Sprintf(`__load("%s", "%s")`, href, target[0].ID))}
 	}
-}
 
-func (ctx *Context) Load(href string) Attr {
 	return Attr{OnClick: Normalize(fmt.Sprintf(`__load("%s")`, href))}
 }
 
+// PushURL changes the address in the browser after the action, adding new history entry.
+func (ctx *Context) PushURL(href string) {
+	ctx.url(href, false)
+}
+
+// ReplaceURL changes the address in the browser after the action, replacing current history entry.
+func (ctx *Context) ReplaceURL(href string) {
+	ctx.url(href, true)
+}
+
+func (ctx *Context) url(href string, replace bool) {
+	if ctx.App != nil && strings.HasPrefix(href, "/") {
+		href = ctx.App.URL(href)
+	}
+
+	if ctx.Partial() {
+		if replace {
+			ctx.Response.Header().Set("X-SRUI-Replace-Url", href)
+		} else {
+			ctx.Response.Header().Set("X-SRUI-Push-Url", href)
+		}
+
+		return
+	}
+
+	ctx.append = append(ctx.append, fmt.Sprintf(`<script>__url("%s", %t);</script>`, Normalize(href), replace))
+}
+
+// Reload reloads the page in the browser, for actions it is done by X-SRUI-Refresh header before anything is swapped.
 func (ctx *Context) Reload() string {
+	if ctx.Partial() {
+		ctx.Response.Header().Set("X-SRUI-Refresh", "true")
+		return ""
+	}
+
 	// return Normalize("<html><!DOCTYPE html><body><script>window.location.reload();</script></body></html>")
 	return Normalize("<script>window.location.reload();</script>")
 }
 
+// Redirect sends the browser to href, for actions by X-SRUI-Redirect header, otherwise by HTTP redirect.
 func (ctx *Context) Redirect(href string) string {
-	// return Normalize(fmt.Sprintf("<html><!DOCTYPE html><body><script>window.location.href = '%s';</script></body></html>", href))
-	return Normalize(fmt.Sprintf("<script>window.location.href = '%s';</script>", href))
+	if ctx.App != nil && strings.HasPrefix(href, "/") {
+		href = ctx.App.URL(href)
+	}
+
+	if ctx.Partial() {
+		ctx.Response.Header().Set("X-SRUI-Redirect", href)
+		return ""
+	}
+
+	http.Redirect(ctx.Response, ctx.Request, href, http.StatusFound)
+	return ""
+}
+
+// Retarget swaps the action result into another element than the action target, e.g. validation errors.
+func (ctx *Context) Retarget(target Attr) {
+	ctx.Response.Header().Set("X-SRUI-Retarget", target.ID)
+}
+
+// Reswap changes how the action result is swapped.
+func (ctx *Context) Reswap(swap Swap) {
+	ctx.Response.Header().Set("X-SRUI-Reswap", string(swap))
 }
 
 func displayMessage(ctx *Context, message string, color string) {
 	ctx.append = append(ctx.append,
-		Trim((`<script>
-            (function() {
-                const el = document.getElementById("__messages__");
-                if(el == null) {
-                    const loader = document.createElement("div");
-                    loader.id = "__messages__";
-                    loader.classList = "fixed top-0 right-0 p-2 z-40";
-                    document.body.appendChild(loader);
-                }
-            })();
-        </script>`)),
-
-		Trim(fmt.Sprintf(`<script>
-            (function () {
-                const el = document.getElementById("__messages__");
-                if(el != null) {
-                    const loader = document.createElement("div");
-                    loader.classList = "p-4 m-2 rounded text-center border border-gray-700 shadow-xl text-xl text-center w-64 %s";
-                    loader.innerHTML = "%s";
-                    el.appendChild(loader);
-					setTimeout(() => el.removeChild(loader), 5000);
-                }
-            })();
-        </script>`, color, Normalize(message))),
+		fmt.Sprintf(`<script>__message("%s", "%s");</script>`, Normalize(message), color),
 	)
 }
 
+// Patch queues html swapped into the target element after the main swap of the action, so one action can update several parts of the page.
+func (ctx *Context) Patch(target Attr, html string, swap Swap) {
+	ctx.patches = append(ctx.patches, fmt.Sprintf(`<template data-srui-target="%s" data-srui-swap="%s">%s</template>`, target.ID, swap, html))
+}
+
 func (ctx *Context) Success(message string) {
 	displayMessage(ctx, message, "bg-green-700 text-white")
 }
 
+// Error shows the message, failed actions (non 2xx status) showing it skip the default error toast.
 func (ctx *Context) Error(message string) {
+	ctx.Response.Header().Set("X-SRUI-Handled", "true")
 	displayMessage(ctx, message, "bg-red-700 text-white")
 }
 
@@ -530,7 +785,12 @@ func (ctx *Context) DownloadAs(file *io.Reader, contentType string, name string)
 	return nil
 }
 
+// Translate formats the message with values like fmt.Sprintf, message without values is returned as it is.
 func (ctx *Context) Translate(message string, val ...any) string {
+	if len(val) == 0 {
+		return message
+	}
+
 	return fmt.Sprintf(message, val...)
 }
 
@@ -558,108 +818,413 @@ func cacheControlMiddleware(next http.Handler, maxAge time.Duration) http.Handle
 	})
 }
 
-type App struct {
-	Lanugage string
-	HTMLBody func(string) string
-	HTMLHead []string
+type route struct {
+	name       string
+	method     string
+	path       string
+	callable   *Callable
+	middleware []Middleware
 }
 
-func (app *App) Register(httpMethod string, path string, method *Callable) string {
-	if path == "" || method == nil {
-		panic("Path and Method cannot be empty")
-	}
+type RouteKind string
 
-	funcName := runtime.FuncForPC(reflect.ValueOf(*method).Pointer()).Name()
+const (
+	PAGE   RouteKind = "page"
+	ACTION RouteKind = "action"
+)
 
-	if funcName == "" {
-		panic("Method cannot be empty")
-	}
+// Route describes registered route, see App.Routes.
+type Route struct {
+	Path   string
+	Method string
+	Kind   RouteKind
+	Name   string
+}
 
-	_, ok := stored[method]
-	if ok {
-		panic("Method already registered: " + funcName)
-	}
+var methods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
 
-	for _, value := range stored {
-		if value == path {
-			panic("Path already registered: " + path)
-		}
+type App struct {
+	Lanugage   string
+	HTMLBody   func(string) string
+	HTMLHead   []string
+	Config     ServerConfig
+	Loading    LoadingConfig
+	prefix     string
+	server     *http.Server
+	done       chan struct{}
+	mu         sync.Mutex
+	stored     map[*Callable]string
+	paths      map[string]map[string]*route
+	names      map[uintptr]string
+	conflicts  []string
+	started    bool
+	middleware []Middleware
+	cache      bool
+	key        []byte
+	random     []byte
+	sealed     map[string]*sealed
+	swept      time.Time
+	validate   *validator.Validate
+	mux        *http.ServeMux
+	hooks      struct {
+		notFound         Callable
+		methodNotAllowed Callable
+		onError          func(*Context, any) string
+		clientError      string
 	}
+}
 
-	mu.Lock()
-	stored[method] = path
-	mu.Unlock()
-
-	// fmt.Println("Registering: ", httpMethod, path, " -> ", funcName)
+// Use adds global middleware, applied to every page and action before the route middleware.
+func (app *App) Use(middleware ...Middleware) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
 
-	return path
+	app.middleware = append(app.middleware, middleware...)
 }
 
-func (app *App) Page(path string, component Callable) **Callable {
-	for key, value := range stored {
-		if value == path {
-			return &key
-		}
+// Recover is middleware which renders Error instead of crashing on panic.
+func Recover(next Callable) Callable {
+	return func(ctx *Context) (html string) {
+		defer func() {
+			if r := recover(); r != nil {
+				html = Error(fmt.Errorf("panic: %v", r))
+			}
+		}()
+
+		return next(ctx)
 	}
+}
 
-	found := &component
+// Timeout is middleware limiting the request context of the route, it can only shorten App.Config.ActionTimeout.
+func Timeout(timeout time.Duration) Middleware {
+	return func(next Callable) Callable {
+		return func(ctx *Context) string {
+			temp, cancel := context.WithTimeout(ctx.Ctx(), timeout)
+			defer cancel()
 
-	mu.Lock()
-	stored[found] = path
-	mu.Unlock()
+			request := ctx.Request
+			ctx.Request = request.WithContext(temp)
+			defer func() { ctx.Request = request }()
 
-	return &found
+			return next(ctx)
+		}
+	}
 }
 
-func (app *App) Action(uid string, action Callable) **Callable {
-	if !strings.HasPrefix(uid, eventPath) {
-		uid = eventPath + uid
-	}
+func (app *App) chain(found *route) Callable {
+	app.mu.Lock()
+	global := app.middleware
+	app.mu.Unlock()
 
-	uid = strings.ToLower(uid)
+	call := *found.callable
 
-	for key, value := range stored {
-		if value == uid {
-			return &key
-		}
+	for i := len(found.middleware) - 1; i >= 0; i-- {
+		call = found.middleware[i](call)
 	}
 
-	found := &action
-	app.Register("POST", uid, found)
+	for i := len(global) - 1; i >= 0; i-- {
+		call = global[i](call)
+	}
 
-	return &found
+	return call
 }
 
-func (app *App) Callable(action Callable) **Callable {
-	uid := runtime.FuncForPC(reflect.ValueOf(action).Pointer()).Name()
+func (app *App) path(method *Callable) (string, bool) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	path, ok := app.stored[method]
+	return path, ok
+}
+
+func (app *App) Register(httpMethod string, path string, method *Callable, middleware ...Middleware) string {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	return app.register(httpMethod, path, method, middleware...)
+}
+
+// pattern converts registered path into http.ServeMux pattern, paths ending with slash match exactly.
+func pattern(path string) string {
+	if strings.HasSuffix(path, "/") {
+		return path + "{$}"
+	}
+
+	return path
+}
+
+func (app *App) register(httpMethod string, path string, method *Callable, middleware ...Middleware) string {
+	if path == "" || method == nil {
+		panic("Path and Method cannot be empty")
+	}
+
+	funcName := runtime.FuncForPC(reflect.ValueOf(*method).Pointer()).Name()
+
+	if funcName == "" {
+		panic("Method cannot be empty")
+	}
+
+	httpMethod = strings.ToUpper(httpMethod)
+
+	if !slices.Contains(methods, httpMethod) {
+		panic("Unsupported HTTP method: " + httpMethod)
+	}
+
+	_, ok := app.stored[method]
+	if ok {
+		panic("Method already registered: " + funcName)
+	}
+
+	_, ok = app.paths[path][httpMethod]
+	if ok {
+		panic("Path already registered: " + httpMethod + " " + path)
+	}
+
+	if app.paths[path] == nil {
+		app.paths[path] = make(map[string]*route)
+		app.mux.Handle(pattern(path), app.handle(path))
+	}
+
+	app.stored[method] = path
+	app.paths[path][httpMethod] = &route{
+		name:       funcName,
+		method:     httpMethod,
+		path:       path,
+		callable:   method,
+		middleware: middleware,
+	}
+
+	// fmt.Println("Registering: ", httpMethod, path, " -> ", funcName)
+
+	return path
+}
+
+func (app *App) Page(path string, component Callable, middleware ...Middleware) **Callable {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	if found, ok := app.paths[path][http.MethodGet]; ok {
+		return &found.callable
+	}
+
+	found := &component
+	app.register("GET", path, found, middleware...)
+
+	return &found
+}
+
+func (app *App) Action(uid string, action Callable, middleware ...Middleware) **Callable {
+	if !strings.HasPrefix(uid, eventPath) {
+		uid = eventPath + uid
+	}
+
 	uid = strings.ToLower(uid)
+
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	pc := reflect.ValueOf(action).Pointer()
+
+	if found, ok := app.paths[uid][http.MethodPost]; ok {
+		if funcName := runtime.FuncForPC(pc).Name(); found.name != funcName {
+			app.conflict(uid, found.name, funcName)
+		}
+
+		return &found.callable
+	}
+
+	for other, value := range app.names {
+		if value == uid && other != pc {
+			app.conflict(uid, runtime.FuncForPC(other).Name(), runtime.FuncForPC(pc).Name())
+		}
+	}
+
+	found := &action
+	app.register("POST", uid, found, middleware...)
+
+	return &found
+}
+
+// actionPath builds stable URL for the function. Readable part is made of package name, receiver and method,
+// hash of the full name keeps apart functions from different packages or receivers with the same readable part.
+func actionPath(funcName string) string {
+	name := strings.TrimSuffix(funcName, "-fm")
+	hash := fnv.New32a()
+	hash.Write([]byte(name))
+
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	uid := strings.ToLower(name)
 	uid = reRemoveChars.ReplaceAllString(uid, "")
 	uid = reReplaceChars.ReplaceAllString(uid, "-")
+	uid = reDashes.ReplaceAllString(uid, "-")
+
+	return fmt.Sprintf("%s%s-%08x", eventPath, strings.Trim(uid, "-"), hash.Sum32())
+}
+
+// Name gives the action an explicit URL, which stays the same when code is reordered.
+// Closures get names like "main.main.func1" from the compiler, so they should be always named.
+func (app *App) Name(name string, action Callable) {
+	uid := strings.ToLower(name)
 
 	if !strings.HasPrefix(uid, eventPath) {
 		uid = eventPath + uid
 	}
 
-	for key, value := range stored {
-		if value == uid {
-			return &key
+	pc := reflect.ValueOf(action).Pointer()
+
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	for other, value := range app.names {
+		if value == uid && other != pc {
+			app.conflict(uid, runtime.FuncForPC(other).Name(), runtime.FuncForPC(pc).Name())
 		}
 	}
 
+	if found, ok := app.paths[uid][http.MethodPost]; ok && found.name != runtime.FuncForPC(pc).Name() {
+		app.conflict(uid, found.name, runtime.FuncForPC(pc).Name())
+	}
+
+	app.names[pc] = uid
+}
+
+// conflict records actions resolving to the same URL. Actions are registered lazily by ctx.Call,
+// conflicts found while serving are logged and the first registered action keeps the URL.
+func (app *App) conflict(path string, funcs ...string) {
+	message := path + " <- " + strings.Join(funcs, ", ")
+
+	if slices.Contains(app.conflicts, message) {
+		return
+	}
+
+	app.conflicts = append(app.conflicts, message)
+
+	if app.started {
+		log.Println("Action path conflict, use App.Name to tell them apart:", message)
+	}
+}
+
+// Validate returns error listing actions which resolve to the same URL.
+func (app *App) Validate() error {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	if len(app.conflicts) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("conflicting action paths, use App.Name to tell them apart:\n\t%s", strings.Join(app.conflicts, "\n\t"))
+}
+
+func (app *App) start() error {
+	if err := app.Validate(); err != nil {
+		return err
+	}
+
+	app.mu.Lock()
+	app.started = true
+	app.mu.Unlock()
+
+	return nil
+}
+
+func (app *App) Callable(action Callable) **Callable {
+	pc := reflect.ValueOf(action).Pointer()
+	funcName := runtime.FuncForPC(pc).Name()
+
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	uid, ok := app.names[pc]
+	if !ok {
+		uid = actionPath(funcName)
+	}
+
+	if found, ok := app.paths[uid][http.MethodPost]; ok {
+		if found.name != funcName {
+			app.conflict(uid, found.name, funcName)
+		}
+
+		return &found.callable
+	}
+
 	found := &action
-	app.Register("POST", uid, found)
+	app.register("POST", uid, found)
 
 	return &found
 }
 
+// Routes returns all registered pages and actions sorted by path.
+func (app *App) Routes() []Route {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	var result []Route
+
+	for _, routes := range app.paths {
+		for _, found := range routes {
+			kind := ACTION
+			if found.method == http.MethodGet {
+				kind = PAGE
+			}
+
+			result = append(result, Route{
+				Path:   found.path,
+				Method: found.method,
+				Kind:   kind,
+				Name:   found.name,
+			})
+		}
+	}
+
+	slices.SortFunc(result, func(a, b Route) int {
+		if a.Path == b.Path {
+			return strings.Compare(a.Method, b.Method)
+		}
+
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return result
+}
+
+// DebugRoutes registers page at /_srui/routes listing all registered routes. Do not enable it in production.
+func (app *App) DebugRoutes(enable bool) {
+	if !enable {
+		return
+	}
+
+	app.Page("/_srui/routes", func(ctx *Context) string {
+		routes := app.Routes()
+		table := SimpleTable(4, "w-full text-left text-sm bg-white rounded-lg shadow")
+		table.Class(0, "p-2 font-mono").Class(1, "p-2").Class(2, "p-2").Class(3, "p-2 font-mono text-gray-600")
+
+		table.Field("Path", "font-bold").Field("Method", "font-bold").Field("Kind", "font-bold").Field("Function", "font-bold")
+
+		for _, route := range routes {
+			table.Field(route.Path).Field(route.Method).Field(string(route.Kind)).Field(route.Name)
+		}
+
+		return app.HTML("Routes", "p-4 bg-gray-200 min-h-screen",
+			Div("max-w-6xl mx-auto flex flex-col gap-4")(
+				Div("text-2xl font-bold")(ctx.Translate("Registered routes (%d)", len(routes))),
+				table.Render(),
+			),
+		)
+	})
+}
+
 func (app *App) Assets(assets embed.FS, path string, maxAge time.Duration) {
 	path = strings.TrimPrefix(path, "/")
-	http.Handle("/"+path, cacheControlMiddleware(http.FileServer(http.FS(assets)), maxAge))
+	app.mux.Handle("/"+path, cacheControlMiddleware(http.FileServer(http.FS(assets)), maxAge))
 }
 
 func (app *App) Favicon(assets embed.FS, path string, maxAge time.Duration) {
 	path = strings.TrimPrefix(path, "/")
-	http.HandleFunc("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {
+	app.mux.HandleFunc("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {
 		file, err := assets.ReadFile(path)
 		if err != nil {
 			http.Error(w, "File not found", http.StatusNotFound)
@@ -676,11 +1241,17 @@ func makeContext(app *App, r *http.Request, w http.ResponseWriter) *Context {
 
 	cookie, err := r.Cookie("session_id")
 	if err != nil {
+		// the prefix without trailing slash, so the mount root gets the cookie too
+		path := app.prefix
+		if path == "" {
+			path = "/"
+		}
+
 		sessionID = RandomString(30)
 		http.SetCookie(w, &http.Cookie{
 			Name:     "session_id",
 			Value:    sessionID,
-			Path:     "/",
+			Path:     path,
 			HttpOnly: true,
 			Secure:   true,
 			SameSite: http.SameSiteStrictMode,
@@ -695,45 +1266,392 @@ func makeContext(app *App, r *http.Request, w http.ResponseWriter) *Context {
 		Response:  w,
 		SessionID: sessionID,
 		append:    []string{},
+		patches:   []string{},
 	}
 }
 
-func (app *App) Listen(port string) {
-	log.Println("Listening on http://0.0.0.0" + port)
+// allowed returns route registered for the path and request method, or the list of allowed methods.
+func (app *App) allowed(path string, method string) (*route, []string) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	routes := app.paths[path]
+
+	if found, ok := routes[method]; ok {
+		return found, nil
+	}
+
+	if found, ok := routes[http.MethodGet]; ok && method == http.MethodHead {
+		return found, nil
+	}
+
+	var allow []string
+
+	for _, value := range methods {
+		if _, ok := routes[value]; !ok {
+			continue
+		}
+
+		allow = append(allow, value)
+
+		if value == http.MethodGet {
+			allow = append(allow, http.MethodHead)
+		}
+	}
+
+	return nil, allow
+}
 
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		if !strings.Contains("GET POST", r.Method) {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+func (app *App) handle(path string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := makeContext(app, r, w)
+		found, allow := app.allowed(path, r.Method)
+
+		if found == nil {
+			w.Header().Set("Allow", strings.Join(allow, ", "))
+			app.serve(ctx, http.StatusMethodNotAllowed, app.methodNotAllowed)
 			return
 		}
 
-		value := r.URL.Path
+		if timeout := app.Config.ActionTimeout; timeout > 0 && found.method != http.MethodGet {
+			temp, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			ctx.Request = r.WithContext(temp)
+		}
+
+		app.serve(ctx, http.StatusOK, app.chain(found))
+	}
+}
+
+func (app *App) dispatch(w http.ResponseWriter, r *http.Request) {
+	if strings.Contains(strings.Join(r.Header["Upgrade"], " "), "websocket") {
+		fmt.Println("a web socket")
+		return
+	}
+
+	app.serve(makeContext(app, r, w), http.StatusNotFound, app.notFound)
+}
+
+// run calls the method, panic is recovered and rendered by OnError hook.
+func (app *App) run(ctx *Context, status int, method Callable) (html string, code int) {
+	defer func() {
+		if r := recover(); r != nil {
+			html, code = app.onError(ctx, r), http.StatusInternalServerError
+		}
+	}()
+
+	return method(ctx), status
+}
+
+func (app *App) serve(ctx *Context, status int, method Callable) {
+	html, status := app.run(ctx, status, method)
+	w := ctx.Response
+
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	}
+
+	if status != http.StatusOK {
+		w.WriteHeader(status)
+	}
+
+	w.Write([]byte(html))
+
+	// messages and patches are html, other responses (e.g. ctx.JSON) are written as they are
+	if mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type")); mediaType != "text/html" {
+		return
+	}
+
+	if len(ctx.append) > 0 {
+		w.Write([]byte(strings.Join(ctx.append, "")))
+	}
+
+	if len(ctx.patches) > 0 {
+		w.Write([]byte(strings.Join(ctx.patches, "")))
+	}
+}
+
+// NotFound sets the page rendered for unknown paths, actions posted by stale tabs get "please reload" message.
+func (app *App) NotFound(method Callable) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	app.hooks.notFound = method
+}
+
+// MethodNotAllowed sets the page rendered when a path exists but not for the request method.
+func (app *App) MethodNotAllowed(method Callable) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	app.hooks.methodNotAllowed = method
+}
+
+// OnError sets the handler rendering a recovered panic.
+func (app *App) OnError(method func(*Context, any) string) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	app.hooks.onError = method
+}
+
+// OnClientError sets script run in the browser when an action or load fails.
+// The script is the body of function(error), error has status (0 for network failure), message and path.
+func (app *App) OnClientError(script string) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	app.hooks.clientError = script
+}
+
+func (app *App) notFound(ctx *Context) string {
+	app.mu.Lock()
+	hook := app.hooks.notFound
+	app.mu.Unlock()
+
+	// stale tab posting to an action which no longer exists after deploy
+	if ctx.Partial() && ctx.Request.Method == http.MethodPost {
+		return app.failure(ctx, ctx.Translate("This page is out of date, please reload it."))
+	}
+
+	if hook != nil {
+		return hook(ctx)
+	}
+
+	return app.failure(ctx, ctx.Translate("Page not found"))
+}
+
+func (app *App) methodNotAllowed(ctx *Context) string {
+	app.mu.Lock()
+	hook := app.hooks.methodNotAllowed
+	app.mu.Unlock()
+
+	if hook != nil {
+		return hook(ctx)
+	}
+
+	return app.failure(ctx, ctx.Translate("Method not allowed"))
+}
+
+func (app *App) onError(ctx *Context, err any) string {
+	app.mu.Lock()
+	hook := app.hooks.onError
+	app.mu.Unlock()
+
+	if hook != nil {
+		return hook(ctx, err)
+	}
+
+	log.Printf("panic: %v\n%s", err, debug.Stack())
+
+	return app.failure(ctx, ctx.Translate("Opps, something went wrong"))
+}
+
+// failure renders message as a toast for requests made by actions, otherwise as a whole page.
+func (app *App) failure(ctx *Context, message string) string {
+	if ctx.Partial() {
+		ctx.Error(message)
+		return ""
+	}
+
+	return app.HTML(message, "",
+		Div("max-w-xl mx-auto mt-24 bg-red-500 text-white font-bold p-8 text-center border border-red-800 rounded-lg")(message),
+	)
+}
+
+// Mount sets the path prefix under which the app is served, e.g. "/admin".
+// Registered paths stay relative to the prefix, generated URLs include it.
+func (app *App) Mount(prefix string) {
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	if prefix != "" && !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+
+	app.prefix = prefix
+}
+
+// URL returns the public URL for a path registered in the app.
+func (app *App) URL(path string) string {
+	return app.prefix + path
+}
+
+// Handler returns the app as http.Handler, so it can be mounted into an existing server.
+// It panics when actions resolve to the same URL, see Validate.
+func (app *App) Handler() http.Handler {
+	if err := app.start(); err != nil {
+		panic(err)
+	}
+
+	return app
+}
+
+func (app *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if app.prefix == "" {
+		app.mux.ServeHTTP(w, r)
+		return
+	}
+
+	path, ok := strings.CutPrefix(r.URL.Path, app.prefix)
+	if !ok || (path != "" && !strings.HasPrefix(path, "/")) {
+		app.serve(makeContext(app, r, w), http.StatusNotFound, app.notFound)
+		return
+	}
+
+	if path == "" {
+		path = "/"
+	}
+
+	temp := new(http.Request)
+	*temp = *r
+	temp.URL = new(url.URL)
+	*temp.URL = *r.URL
+	temp.URL.Path = path
+	temp.URL.RawPath = ""
+
+	app.mux.ServeHTTP(w, temp)
+}
+
+// LoadingConfig holds markup of the loading indicators and the delay before they show up.
+type LoadingConfig struct {
+	Overlay string
+	Spinner string
+	Delay   time.Duration
+}
+
+// ServerConfig holds settings of http.Server used by Listen and ListenWithContext.
+// ActionTimeout limits context of every action (non GET route), see Context.Ctx.
+// MaxBodyBytes limits posted forms and uploaded files of actions, BodyLimit changes it per route.
+// ValuesTTL is how long server only values (`srui:"server"` fields) are kept for actions,
+// ValuesLimit caps how many of them are kept, the ones expiring first are dropped.
+// TLS is enabled when CertFile and KeyFile or TLSConfig (e.g. from autocert) is set.
+type ServerConfig struct {
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
+	ActionTimeout     time.Duration
+	ValuesTTL         time.Duration
+	ValuesLimit       int
+	MaxBodyBytes      int64
+	MaxHeaderBytes    int
+	CertFile          string
+	KeyFile           string
+	TLSConfig         *tls.Config
+}
+
+// Listen serves the app until SIGINT or SIGTERM is received, then drains in-flight requests.
+func (app *App) Listen(port string) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := app.ListenWithContext(ctx, port); err != nil {
+		log.Println("Error:", err)
+	}
+}
+
+// ListenWithContext serves the app until ctx is done, then shuts the server down gracefully.
+func (app *App) ListenWithContext(ctx context.Context, addr string) error {
+	if err := app.start(); err != nil {
+		return err
+	}
+
+	config := app.Config
+	secure := config.TLSConfig != nil || (config.CertFile != "" && config.KeyFile != "")
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           app,
+		ReadTimeout:       config.ReadTimeout,
+		ReadHeaderTimeout: config.ReadHeaderTimeout,
+		WriteTimeout:      config.WriteTimeout,
+		IdleTimeout:       config.IdleTimeout,
+		MaxHeaderBytes:    config.MaxHeaderBytes,
+		TLSConfig:         config.TLSConfig,
+	}
+
+	app.mu.Lock()
+	app.server = server
+
+	// app can be started again after Shutdown, live reload needs open channel
+	select {
+	case <-app.done:
+		app.done = make(chan struct{})
+	default:
+	}
+
+	done := app.done
+	app.mu.Unlock()
+
+	server.RegisterOnShutdown(func() { app.close(done) })
+
+	errs := make(chan error, 1)
+
+	go func() {
+		if secure {
+			log.Println("Listening on https://0.0.0.0" + addr + app.prefix)
+			errs <- server.ListenAndServeTLS(config.CertFile, config.KeyFile)
+		} else {
+			log.Println("Listening on http://0.0.0.0" + addr + app.prefix)
+			errs <- server.ListenAndServe()
+		}
+	}()
+
+	select {
+	case err := <-errs:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+
+		return err
+
+	case <-ctx.Done():
+		timeout := config.ShutdownTimeout
+		if timeout <= 0 {
+			timeout = 15 * time.Second
+		}
+
+		shutdown, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
 
-		if strings.Contains(strings.Join(r.Header["Upgrade"], " "), "websocket") {
-			fmt.Println("a web socket")
-			return
-		}
+		return app.Shutdown(shutdown)
+	}
+}
 
-		for found, path := range stored {
-			if value == path {
-				ctx := makeContext(app, r, w)
+// Shutdown stops the server started by Listen or ListenWithContext, waiting for in-flight requests until ctx is done.
+func (app *App) Shutdown(ctx context.Context) error {
+	app.stop()
 
-				w.Header().Set("Content-Type", "text/html; charset=utf-8")
-				w.Write([]byte((*found)(ctx)))
+	app.mu.Lock()
+	server := app.server
+	app.mu.Unlock()
 
-				if len(ctx.append) > 0 {
-					w.Write([]byte(strings.Join(ctx.append, "")))
-				}
+	if server == nil {
+		return nil
+	}
 
-				return
-			}
-		}
+	return server.Shutdown(ctx)
+}
 
-		http.Error(w, "Not found", http.StatusNotFound)
-	})
+func (app *App) stop() {
+	app.mu.Lock()
+	done := app.done
+	app.mu.Unlock()
 
-	if err := http.ListenAndServe(port, nil); err != nil && !errors.Is(err, http.ErrServerClosed) {
-		log.Println("Error:", err)
+	app.close(done)
+}
+
+// close closes live reload connections of the server.
+func (app *App) close(done chan struct{}) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	select {
+	case <-done:
+	default:
+		close(done)
 	}
 }
 
@@ -742,50 +1660,577 @@ func (app *App) Autoreload(enable bool) {
 		app.HTMLHead = append(app.HTMLHead, `
 		<script>
 			const protocol = window.location.protocol === 'https:' ? 'wss://' : 'ws://';
-			const socket = new WebSocket(protocol + window.location.host + '/live');
+			const socket = new WebSocket(protocol + window.location.host + '__prefix__/live');
 			socket.addEventListener('close', function (event) {
 				document.body.innerHTML += '<div class="fixed inset-0 z-40 opacity-75 bg-gray-800"></div>';
 				document.body.innerHTML += '<div class="fixed z-50 top-6 left-6 p-6 text-white bg-red-700 rounded border border-gray-500 uppercase font-bold">Offline</div>';
 				setInterval(() => {
-					fetch('/').then(() => window.location.reload()).catch(() => {});
+					fetch('__prefix__/').then(() => window.location.reload()).catch(() => {});
 				}, 2000);
 			});
 		</script>
 	`)
 
-		http.Handle("/live", websocket.Handler(func(ws *websocket.Conn) {
+		app.mux.Handle("/live", websocket.Handler(func(ws *websocket.Conn) {
 			defer ws.Close()
 
+			// hijacked connection keeps deadlines from server timeouts
+			ws.SetDeadline(time.Time{})
+
+			app.mu.Lock()
+			done := app.done
+			app.mu.Unlock()
+
+			ticker := time.NewTicker(10 * time.Second)
+			defer ticker.Stop()
+
 			for {
-				time.Sleep(10 * time.Second)
-				ws.Write([]byte("ok"))
+				select {
+				case <-done:
+					return
+
+				case <-ticker.C:
+					if _, err := ws.Write([]byte("ok")); err != nil {
+						return
+					}
+				}
 			}
 		}))
 	}
 }
 
+// HistoryCache keeps snapshots of pages left by ctx.Load or ctx.PushURL in the browser,
+// back and forward buttons then restore them without a request.
+func (app *App) HistoryCache(enable bool) {
+	app.cache = enable
+}
+
 func (app *App) Description(description string) {
 	app.HTMLHead = append(app.HTMLHead, `<meta name="description" content="`+description+`">`)
 }
 
+// client renders settings read by the client runtime.
+func (app *App) client() string {
+	config, err := json.Marshal(map[string]any{
+		"overlay": app.Loading.Overlay,
+		"spinner": app.Loading.Spinner,
+		"delay":   app.Loading.Delay.Milliseconds(),
+		"content": ContentID.ID,
+		"cache":   app.cache,
+		"confirm": confirmModal(),
+	})
+
+	if err != nil {
+		log.Println(err)
+		return ""
+	}
+
+	app.mu.Lock()
+	script := app.hooks.clientError
+	app.mu.Unlock()
+
+	if script == "" {
+		return `<script>window.__srui = ` + string(config) + `;</script>`
+	}
+
+	return `<script>window.__srui = ` + string(config) + `; window.__srui.onerror = function (error) { ` + script + ` };</script>`
+}
+
 func (app *App) HTML(title string, class string, body ...string) string {
 	head := []string{
 		`<title>` + title + `</title>`,
+		app.client(),
 	}
 
 	head = append(head, app.HTMLHead...)
 
 	html := app.HTMLBody(class)
 	html = strings.ReplaceAll(html, "__lang__", app.Lanugage)
-	html = strings.ReplaceAll(html, "__head__", strings.Join(head, " "))
+	html = strings.ReplaceAll(html, "__head__", strings.ReplaceAll(strings.Join(head, " "), "__prefix__", app.prefix))
 	html = strings.ReplaceAll(html, "__body__", strings.Join(body, " "))
 
 	return Trim(html)
 }
 
+var __morph = Trim(`
+    function __morph(el, html) {
+		const template = document.createElement("template");
+		template.innerHTML = html;
+
+		const nodes = [...template.content.childNodes].filter(node => node.nodeType !== Node.TEXT_NODE || node.textContent.trim() !== "");
+		if (nodes.length !== 1 || nodes[0].nodeName !== el.nodeName) {
+			el.outerHTML = html;
+			return;
+		}
+
+		__morphNode(el, nodes[0]);
+    }
+
+    function __morphNode(from, to) {
+		if (from.nodeType !== Node.ELEMENT_NODE) {
+			if (from.nodeValue !== to.nodeValue) {
+				from.nodeValue = to.nodeValue;
+			}
+			return;
+		}
+
+		const active = from === document.activeElement;
+
+		for (const attr of [...from.attributes]) {
+			if (!to.hasAttribute(attr.name) && !(from.tagName === "DETAILS" && attr.name === "open")) {
+				from.removeAttribute(attr.name);
+			}
+		}
+
+		for (const attr of [...to.attributes]) {
+			if (active && attr.name === "value") {
+				continue;
+			}
+			if (from.tagName === "DETAILS" && attr.name === "open") {
+				continue;
+			}
+			if (from.getAttribute(attr.name) !== attr.value) {
+				from.setAttribute(attr.name, attr.value);
+			}
+		}
+
+		__morphChildren(from, to);
+
+		if (active) {
+			return;
+		}
+
+		if (from.tagName === "INPUT") {
+			from.checked = to.checked;
+			if (from.value !== to.value) {
+				from.value = to.value;
+			}
+		} else if (from.tagName === "TEXTAREA" || from.tagName === "SELECT") {
+			if (from.value !== to.value) {
+				from.value = to.value;
+			}
+		}
+    }
+
+    function __morphChildren(from, to) {
+		let current = from.firstChild;
+
+		[...to.childNodes].forEach(node => {
+			let match = null;
+
+			if (node.nodeType === Node.ELEMENT_NODE && node.id) {
+				match = [...from.children].find(child => child.id === node.id && child.nodeName === node.nodeName) || null;
+			}
+
+			if (match == null && current != null && current.nodeName === node.nodeName) {
+				if (!(current.nodeType === Node.ELEMENT_NODE && current.id && current.id !== node.id)) {
+					match = current;
+				}
+			}
+
+			if (match == null) {
+				from.insertBefore(node, current);
+				return;
+			}
+
+			if (match === current) {
+				current = current.nextSibling;
+			} else {
+				from.insertBefore(match, current);
+			}
+
+			__morphNode(match, node);
+		});
+
+		while (current != null) {
+			const next = current.nextSibling;
+			from.removeChild(current);
+			current = next;
+		}
+    }
+`)
+
+var __trigger = Trim(`
+    function __bind(el) {
+		const text = el.getAttribute("data-srui-trigger");
+		if (el.__srui_trigger) {
+			if (el.__srui_trigger.text === text) {
+				return;
+			}
+			el.__srui_trigger.stop();
+			delete el.__srui_trigger;
+		}
+		if (text == null) {
+			return;
+		}
+
+		const spec = JSON.parse(text);
+		const action = new Function("event", spec.action);
+		const fire = (event) => action.call(el, event || {target: el, preventDefault: () => {}});
+
+		let timer;
+		let interval;
+		let observer;
+
+		let handler = fire;
+		if (spec.debounce) {
+			handler = (event) => {
+				clearTimeout(timer);
+				timer = setTimeout(() => fire(event), spec.debounce);
+			};
+		} else if (spec.throttle) {
+			let last = 0;
+			handler = (event) => {
+				const now = Date.now();
+				if (now - last >= spec.throttle) {
+					last = now;
+					fire(event);
+				}
+			};
+		}
+
+		if (spec.event) {
+			el.addEventListener(spec.event, handler);
+		}
+
+		if (spec.every) {
+			interval = setInterval(() => {
+				if (!el.isConnected) {
+					clearInterval(interval);
+					return;
+				}
+				handler();
+			}, spec.every);
+		}
+
+		if (spec.visible) {
+			observer = new IntersectionObserver(entries => {
+				if (entries.some(entry => entry.isIntersecting)) {
+					observer.disconnect();
+					handler();
+				}
+			});
+			observer.observe(el);
+		}
+
+		el.__srui_trigger = {
+			text: text,
+			stop: () => {
+				if (spec.event) {
+					el.removeEventListener(spec.event, handler);
+				}
+				clearTimeout(timer);
+				clearInterval(interval);
+				if (observer) {
+					observer.disconnect();
+				}
+			}
+		};
+    }
+
+    function __trigger(root) {
+		if (root.nodeType !== 1 && root.nodeType !== 9) {
+			return;
+		}
+		if (root.nodeType === 1 && root.hasAttribute("data-srui-trigger")) {
+			__bind(root);
+		}
+		root.querySelectorAll("[data-srui-trigger]").forEach(__bind);
+    }
+
+	document.addEventListener("DOMContentLoaded", function () {
+		__trigger(document);
+		new MutationObserver(mutations => {
+			mutations.forEach(mutation => {
+				if (mutation.type === "attributes") {
+					__bind(mutation.target);
+					return;
+				}
+				mutation.addedNodes.forEach(__trigger);
+			});
+		}).observe(document.body, {childList: true, subtree: true, attributes: true, attributeFilter: ["data-srui-trigger"]});
+	});
+`)
+
+var __swap = Trim(`
+    function __swap(el, swap, html) {
+		if (el == null) {
+			return;
+		}
+
+		switch (swap) {
+			case "inline":
+				el.innerHTML = html;
+				break;
+			case "outline":
+				el.outerHTML = html;
+				break;
+			case "append":
+				el.insertAdjacentHTML("beforeend", html);
+				break;
+			case "prepend":
+				el.insertAdjacentHTML("afterbegin", html);
+				break;
+			case "before":
+				el.insertAdjacentHTML("beforebegin", html);
+				break;
+			case "after":
+				el.insertAdjacentHTML("afterend", html);
+				break;
+			case "remove":
+				el.remove();
+				break;
+			case "morph":
+				__morph(el, html);
+				break;
+		}
+    }
+`)
+
+var __process = Trim(`
+    function __process(html, ok, swap, target_id, handled) {
+		const index = html.indexOf('<template data-srui-target=');
+		const main = index < 0 ? html : html.substring(0, index);
+		const parser = new DOMParser();
+		const doc = parser.parseFromString(main, 'text/html');
+		const scripts = ok ? [...doc.body.querySelectorAll('script'), ...doc.head.querySelectorAll('script')] : handled ? [...doc.body.querySelectorAll('script')] : [];
+
+		for (let i = 0; i < scripts.length; i++) {
+			const newScript = document.createElement('script');
+			newScript.textContent = scripts[i].textContent;
+			document.body.appendChild(newScript);
+		}
+
+		if (!ok) {
+			return;
+		}
+
+		__swap(document.getElementById(target_id), swap, main);
+
+		if (index >= 0) {
+			const patches = parser.parseFromString(html.substring(index), 'text/html').querySelectorAll('template[data-srui-target]');
+			patches.forEach(patch => {
+				__swap(document.getElementById(patch.dataset.sruiTarget), patch.dataset.sruiSwap, patch.innerHTML);
+
+				patch.content.querySelectorAll('script').forEach(script => {
+					const newScript = document.createElement('script');
+					newScript.textContent = script.textContent;
+					document.body.appendChild(newScript);
+				});
+			});
+		}
+    }
+`)
+
+var __response = Trim(`
+    function __response(response, swap, target_id, path) {
+		const redirect = response.headers.get("X-SRUI-Redirect");
+		if (redirect) {
+			window.location.href = redirect;
+			return;
+		}
+
+		if (response.headers.get("X-SRUI-Refresh") === "true") {
+			window.location.reload();
+			return;
+		}
+
+		const retarget = response.headers.get("X-SRUI-Retarget");
+		const reswap = response.headers.get("X-SRUI-Reswap");
+		const ok = response.ok || retarget != null || reswap != null;
+
+		const push = response.headers.get("X-SRUI-Push-Url");
+		const replace = response.headers.get("X-SRUI-Replace-Url");
+		if (push) {
+			__url(push, false);
+		} else if (replace) {
+			__url(replace, true);
+		}
+
+		const handled = response.headers.get("X-SRUI-Handled") === "true";
+
+		return response.text().then(html => {
+			__process(html, ok, reswap || swap, retarget || target_id, handled);
+			if (!ok) {
+				__error(response.status, response.statusText, path, handled);
+			}
+		});
+    }
+`)
+
+var __message = Trim(`
+    function __message(message, color) {
+		let el = document.getElementById("__messages__");
+		if (el == null) {
+			el = document.createElement("div");
+			el.id = "__messages__";
+			el.classList = "fixed top-0 right-0 p-2 z-40";
+			document.body.appendChild(el);
+		}
+
+		const loader = document.createElement("div");
+		loader.classList = "p-4 m-2 rounded text-center border border-gray-700 shadow-xl text-xl text-center w-64 " + color;
+		loader.innerHTML = message;
+		el.appendChild(loader);
+		setTimeout(() => loader.remove(), 5000);
+    }
+`)
+
+var __error = Trim(`
+    function __error(status, message, path, handled) {
+		const config = window.__srui || {};
+
+		if (!handled) {
+			__message(status ? "Error " + status + (message ? ": " + message : "") : "Network error, please try again.", "bg-red-700 text-white");
+		}
+
+		if (config.onerror) {
+			config.onerror({status, message, path});
+		}
+    }
+`)
+
+var __retry = Trim(`
+    function __retry(path, init, attempt) {
+		const retry = () => new Promise(resolve => setTimeout(resolve, 250 * Math.pow(2, attempt))).then(() => __retry(path, init, attempt + 1));
+
+		return fetch(path, init).then(
+			response => response.status >= 500 && attempt < 3 ? retry() : response,
+			error => {
+				if (attempt >= 3) {
+					throw error;
+				}
+				return retry();
+			}
+		);
+    }
+`)
+
+var __indicator = Trim(`
+    function __indicator(el, target_id, options) {
+		const config = window.__srui || {overlay: "Loading ...", delay: 100};
+		const indicator = (options && options.indicator) || "overlay";
+
+		if (indicator === "none") {
+			return () => {};
+		}
+
+		if (indicator.startsWith("#")) {
+			const custom = document.getElementById(indicator.substring(1));
+			if (custom == null) {
+				return () => {};
+			}
+			custom.classList.remove("hidden");
+			return () => custom.classList.add("hidden");
+		}
+
+		if (indicator === "disable") {
+			const items = (el.tagName === "FORM" ? [...el.querySelectorAll("button, input[type=submit]")] : [el]).filter(item => !item.disabled);
+			items.forEach(item => item.disabled = true);
+			return () => items.forEach(item => item.disabled = false);
+		}
+
+		let loader;
+		let relative = false;
+		let progress = "";
+		const target = document.getElementById(target_id) || el;
+		const markup = indicator === "spinner" ? config.spinner : config.overlay;
+
+		const loading = setTimeout(() => {
+			loader = document.createElement("div");
+
+			if (indicator === "spinner") {
+				relative = getComputedStyle(target).position === "static";
+				if (relative) {
+					target.style.position = "relative";
+				}
+				loader.classList = "absolute inset-0 flex gap-2 items-center justify-center z-40 bg-white opacity-75";
+				loader.innerHTML = markup + progress;
+				target.appendChild(loader);
+			} else {
+				loader.classList = "fixed inset-0 flex gap-4 items-center justify-center z-50 bg-white opacity-75 font-bold text-3xl";
+				loader.innerHTML = markup + progress;
+				document.body.appendChild(loader);
+			}
+		}, config.delay);
+
+		const stop = () => {
+			clearTimeout(loading);
+			if (loader) {
+				loader.remove();
+			}
+			if (relative) {
+				target.style.position = "";
+			}
+		};
+
+		stop.progress = (percent) => {
+			progress = "<span>" + percent + " %</span>";
+			if (loader) {
+				loader.innerHTML = markup + progress;
+			}
+		};
+
+		return stop;
+    }
+`)
+
+var __confirm = Trim(`
+    function __confirm(options, proceed) {
+		if (!options || !options.confirm) {
+			proceed();
+			return;
+		}
+
+		const config = window.__srui || {};
+		if (!config.confirm) {
+			if (window.confirm(options.confirm.message)) {
+				proceed();
+			}
+			return;
+		}
+
+		const holder = document.createElement("div");
+		holder.innerHTML = config.confirm;
+
+		const modal = holder.firstElementChild;
+		const form = modal.querySelector("form");
+		form.firstElementChild.textContent = options.confirm.message;
+		form.querySelector("[type=reset]").textContent = options.confirm.cancel;
+		form.querySelector("[type=submit]").textContent = options.confirm.ok;
+		const escape = (event) => {
+			if (event.key === "Escape") {
+				close();
+			}
+		};
+		const close = () => {
+			modal.remove();
+			document.removeEventListener("keydown", escape);
+		};
+
+		form.addEventListener("submit", (event) => {
+			event.preventDefault();
+			close();
+			proceed();
+		});
+		form.addEventListener("reset", (event) => {
+			event.preventDefault();
+			close();
+		});
+		document.addEventListener("keydown", escape);
+		document.body.appendChild(modal);
+
+		const ok = form.querySelector("[type=submit]");
+		if (ok != null) {
+			ok.focus();
+		}
+    }
+`)
+
 var __post = Trim(` 
-    function __post(event, swap, target_id, path, values) {
+    function __post(event, swap, target_id, path, values, options) {
 		const el = event.target;
+		const source = event.currentTarget || el;
 		const name = el.getAttribute("name");
 		const type = el.getAttribute("type");
 		const value = el.value;
@@ -796,42 +2241,14 @@ var __post = Trim(`
 			body.push({ name, type, value });
 		}
 
-		let loader;
-		let loading = setTimeout(() => {
-			loader = document.createElement("div");
-			loader.classList = "fixed inset-0 flex gap-4 items-center justify-center z-50 bg-white opacity-75 font-bold text-3xl";
-			loader.innerHTML = "Loading ...";
-			document.body.appendChild(loader);
-		}, 100);
-
-		fetch(path, {method: "POST", body: JSON.stringify(body)})
-			.then(html => html.text())
-			.then(function (html) {
-				const parser = new DOMParser();
-				const doc = parser.parseFromString(html, 'text/html');
-				const scripts = [...doc.body.querySelectorAll('script'), ...doc.head.querySelectorAll('script')];
-
-				for (let i = 0; i < scripts.length; i++) {
-					const newScript = document.createElement('script');
-					newScript.textContent = scripts[i].textContent;
-					document.body.appendChild(newScript);
-				}
+		__confirm(options, () => {
+			const stop = __indicator(source, target_id, options);
 
-				const el = document.getElementById(target_id);
-				if (el != null) {
-					if (swap === "inline") {
-						el.innerHTML = html;
-					} else if(swap === "outline") {
-						el.outerHTML = html;
-					}
-				}
-			})
-			.finally(function() {
-				clearTimeout(loading);
-				if(loader) {
-					document.body.removeChild(loader);
-				}
-			});
+			fetch(path, {method: "POST", headers: {"X-SRUI-Request": "true"}, body: JSON.stringify(body)})
+				.then(response => __response(response, swap, target_id, path))
+				.catch(error => __error(0, String(error), path, false))
+				.finally(stop);
+		});
     }
 `)
 
@@ -877,7 +2294,7 @@ var __stringify = Trim(`
 `)
 
 var __submit = Trim(`
-    function __submit(event, swap, target_id, path, values) {
+    function __submit(event, swap, target_id, path, values, options) {
         event.preventDefault(); 
 
         const el = event.target;
@@ -907,89 +2324,190 @@ var __submit = Trim(`
             }
         });
 
-        let loader;
-        let loading = setTimeout(() => {
-            loader = document.createElement("div");
-            loader.classList = "fixed inset-0 flex gap-4 items-center justify-center z-50 bg-white opacity-75 font-bold text-3xl";
-            loader.innerHTML = "Loading ...";
-            document.body.appendChild(loader);
-        }, 100);
-
-        fetch(path, {method: "POST", body: JSON.stringify(body)})
-            .then(html => html.text())
-			.then(function (html) {
-				const parser = new DOMParser();
-				const doc = parser.parseFromString(html, 'text/html');
-				const scripts = [...doc.body.querySelectorAll('script'), ...doc.head.querySelectorAll('script')];
+        const files = found.filter(item => item.getAttribute("type") === "file");
 
-				for (let i = 0; i < scripts.length; i++) {
-					const newScript = document.createElement('script');
-					newScript.textContent = scripts[i].textContent;
-					document.body.appendChild(newScript);
-				}
+        __confirm(options, () => {
+            const stop = __indicator(form, target_id, options);
+            const request = files.length > 0
+                ? __upload(path, body, files, stop)
+                : fetch(path, {method: "POST", headers: {"X-SRUI-Request": "true"}, body: JSON.stringify(body)});
 
-				const el = document.getElementById(target_id);
-				if (el != null) {
-					if (swap === "inline") {
-						el.innerHTML = html;
-					} else if(swap === "outline") {
-						el.outerHTML = html;
-					}
-				}
-			})
-            .finally(function() {
-                clearTimeout(loading);
-                if(loader) {
-                    document.body.removeChild(loader);
+            request
+                .then(response => __response(response, swap, target_id, path))
+                .catch(error => __error(0, String(error), path, false))
+                .finally(stop);
+        });
+    }
+`)
+
+var __upload = Trim(`
+    function __upload(path, body, files, stop) {
+        return new Promise((resolve, reject) => {
+            const data = new FormData();
+            const names = files.map(item => item.getAttribute("name"));
+
+            body.filter(item => !names.includes(item.name)).forEach(item => data.append(item.name, item.value));
+            files.forEach(item => Array.from(item.files).forEach(file => data.append(item.getAttribute("name"), file)));
+
+            const xhr = new XMLHttpRequest();
+            xhr.open("POST", path);
+            xhr.setRequestHeader("X-SRUI-Request", "true");
+
+            xhr.upload.onprogress = (event) => {
+                if (event.lengthComputable && stop.progress) {
+                    stop.progress(Math.round(event.loaded * 100 / event.total));
                 }
-            });
+            };
+
+            xhr.onload = () => {
+                const headers = new Headers();
+                xhr.getAllResponseHeaders().trim().split(/[\r\n]+/).forEach(line => {
+                    const index = line.indexOf(":");
+                    if (index > 0) {
+                        headers.append(line.substring(0, index).trim(), line.substring(index + 1).trim());
+                    }
+                });
+
+                const empty = [204, 205, 304].includes(xhr.status);
+                resolve(new Response(empty ? null : xhr.responseText, {status: xhr.status, statusText: xhr.statusText, headers}));
+            };
+
+            xhr.onerror = () => reject(new Error("Network error"));
+            xhr.send(data);
+        });
     }
 `)
 
 var __load = Trim(`
-    function __load(href) {
+    var __history = __history || {href: location.href, target_id: "", cache: {}};
+
+    function __load(href, target_id) {
 		event.preventDefault(); 
 
-		let loader;
-		let loading = setTimeout(() => {
-			loader = document.createElement("div");
-			loader.classList = "fixed inset-0 flex gap-4 items-center justify-center z-50 bg-white opacity-75 font-bold text-3xl";
-			loader.innerHTML = "Loading ...";
-			document.body.appendChild(loader);
-		}, 100);
-
-		fetch(href, {method: "GET"})
-			.then(html => html.text())
-			.then(function (html) {
+		__navigate(href, target_id || (window.__srui || {}).content, true);
+    }
+
+    function __snapshot() {
+		const config = window.__srui || {};
+		const el = document.getElementById(__history.target_id || config.content) || document.body;
+
+		if (config.cache) {
+			__history.cache[__history.href] = {title: document.title, target_id: el.id, html: el.innerHTML};
+		}
+    }
+
+    function __url(href, replace) {
+		if (replace) {
+			window.history.replaceState({target_id: __history.target_id}, "", href);
+		} else {
+			__snapshot();
+			window.history.pushState({target_id: __history.target_id}, "", href);
+		}
+
+		__history.href = location.href;
+    }
+
+    function __navigate(href, target_id, push) {
+		const stop = __indicator(document.body, "", {});
+
+		__retry(href, {method: "GET"}, 0)
+			.then(response => response.text().then(html => {
+				if (!response.ok) {
+					__error(response.status, response.statusText, href, false);
+					return;
+				}
+
 				const parser = new DOMParser();
 				const doc = parser.parseFromString(html, 'text/html');
+				const from = doc.getElementById(target_id);
+				const to = document.getElementById(target_id);
+
+				if (push) {
+					__snapshot();
+				}
 
 				document.title = doc.title;
-				document.body.innerHTML = doc.body.innerHTML;
 
-				const scripts = [...doc.body.querySelectorAll('script'), ...doc.head.querySelectorAll('script')];
+				let scripts;
+				if (from != null && to != null) {
+					to.innerHTML = from.innerHTML;
+					scripts = [...from.querySelectorAll('script')];
+				} else {
+					document.body.innerHTML = doc.body.innerHTML;
+					scripts = [...doc.body.querySelectorAll('script'), ...doc.head.querySelectorAll('script')];
+				}
+
 				for (let i = 0; i < scripts.length; i++) {
 					const newScript = document.createElement('script');
 					newScript.textContent = scripts[i].textContent;
 					document.body.appendChild(newScript);
 				}
 
-				window.history.pushState({}, doc.title, href);
-			})
-			.finally(function() {
-				clearTimeout(loading);
-				if(loader) {
-					document.body.removeChild(loader);
+				if (push) {
+					window.history.pushState({target_id}, doc.title, response.redirected ? response.url : href);
 				}
-			});
+
+				__history.href = location.href;
+				__history.target_id = target_id;
+			}))
+			.catch(error => __error(0, String(error), href, false))
+			.finally(stop);
+    }
+
+	if (!__history.bound) {
+		__history.bound = true;
+		window.addEventListener("popstate", __popstate);
+	}
+
+    function __popstate(event) {
+		if (location.href.split("#")[0] === __history.href.split("#")[0]) {
+			__history.href = location.href;
+			return;
+		}
+
+		__snapshot();
+
+		const target_id = (event.state && event.state.target_id) || (window.__srui || {}).content;
+		const cached = __history.cache[location.href];
+		const el = cached ? document.getElementById(cached.target_id) : null;
+
+		if (el != null) {
+			document.title = cached.title;
+			el.innerHTML = cached.html;
+			__history.href = location.href;
+			__history.target_id = cached.target_id;
+			return;
+		}
+
+		__navigate(location.href, target_id, false);
     }
 `)
 
 var ContentID = Target()
 
 func MakeApp(defaultLanguage string) *App {
-	return &App{
+	app := &App{
 		Lanugage: defaultLanguage,
+		stored:   make(map[*Callable]string),
+		paths:    make(map[string]map[string]*route),
+		names:    make(map[uintptr]string),
+		mux:      http.NewServeMux(),
+		done:     make(chan struct{}),
+		Config: ServerConfig{
+			ReadHeaderTimeout: 10 * time.Second,
+			ReadTimeout:       30 * time.Second,
+			WriteTimeout:      60 * time.Second,
+			IdleTimeout:       120 * time.Second,
+			ShutdownTimeout:   15 * time.Second,
+			ValuesTTL:         time.Hour,
+			ValuesLimit:       10000,
+			MaxBodyBytes:      32 << 20,
+		},
+		Loading: LoadingConfig{
+			Overlay: "Loading ...",
+			Spinner: `<svg class="animate-spin h-8 w-8 text-gray-700" viewBox="0 0 24 24" fill="none"><circle class="opacity-25" cx="12" cy="12" r="10" stroke="currentColor" stroke-width="4"></circle><path class="opacity-75" fill="currentColor" d="M4 12a8 8 0 018-8V0C5.373 0 0 5.373 0 12h4z"></path></svg>`,
+			Delay:   100 * time.Millisecond,
+		},
 		HTMLHead: []string{
 			`<meta charset="UTF-8">`,
 			`<meta name="viewport" content="width=device-width, initial-scale=1.0">`,
@@ -1020,7 +2538,7 @@ func MakeApp(defaultLanguage string) *App {
 				}
 			</style>`,
 			`<link rel="stylesheet" href="https://cdnjs.cloudflare.com/ajax/libs/tailwindcss/2.2.19/tailwind.min.css" integrity="sha512-wnea99uKIC3TJF7v4eKk4Y+lMz2Mklv18+r4na2Gn1abDRPPOeef95xTzdwGD9e6zXJBteMIhZ1+68QC5byJZw==" crossorigin="anonymous" referrerpolicy="no-referrer" />`,
-			Script(__stringify, __post, __submit, __load),
+			Script(__stringify, __trigger, __morph, __swap, __process, __response, __message, __error, __retry, __indicator, __confirm, __post, __submit, __upload, __load),
 		},
 		HTMLBody: func(class string) string {
 			if class == "" {
@@ -1036,4 +2554,8 @@ func MakeApp(defaultLanguage string) *App {
 			`, class, ContentID.ID)
 		},
 	}
+
+	app.mux.HandleFunc("/", app.dispatch)
+
+	return app
 }
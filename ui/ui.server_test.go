@@ -0,0 +1,188 @@
+package ui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestActionConflict(t *testing.T) {
+	app := MakeApp("en")
+	first := func(ctx *Context) string { return "first" }
+	second := func(ctx *Context) string { return "second" }
+
+	app.Action("save", first)
+	app.Action("save", second)
+
+	if err := app.Validate(); err == nil {
+		t.Fatal("conflicting actions not reported")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Handler started with conflicting actions")
+		}
+	}()
+
+	app.Handler()
+}
+
+func TestNameConflict(t *testing.T) {
+	first := func(ctx *Context) string { return "first" }
+	second := func(ctx *Context) string { return "second" }
+
+	app := MakeApp("en")
+	app.Action("save", first)
+	app.Name("save", second)
+
+	if err := app.Validate(); err == nil {
+		t.Error("Name clashing with Action not reported")
+	}
+
+	app = MakeApp("en")
+	app.Name("save", second)
+	app.Action("save", first)
+
+	if err := app.Validate(); err == nil {
+		t.Error("Action clashing with Name not reported")
+	}
+
+	app = MakeApp("en")
+	app.Action("save", first)
+	app.Action("save", first)
+
+	if err := app.Validate(); err != nil {
+		t.Errorf("same action registered twice reported: %v", err)
+	}
+}
+
+func TestConflictWhileServing(t *testing.T) {
+	first := func(ctx *Context) string { return "first" }
+	second := func(ctx *Context) string { return "second" }
+
+	app := MakeApp("en")
+	app.Action("save", first)
+	app.Page("/", func(ctx *Context) string {
+		app.Name("save", second)
+		return ctx.Call(second).None()
+	})
+
+	handler := app.Handler()
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest("GET", "/", nil))
+
+	if response.Code != http.StatusOK {
+		t.Errorf("conflict while serving failed the request: %d", response.Code)
+	}
+
+	if err := app.Validate(); err == nil {
+		t.Error("conflict while serving not recorded")
+	}
+
+	response = httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest("POST", "/save", nil))
+
+	if !strings.Contains(response.Body.String(), "first") {
+		t.Errorf("first action does not keep the URL: %s", response.Body.String())
+	}
+}
+
+func TestRouting(t *testing.T) {
+	app := MakeApp("en")
+	app.Page("/orders/{id}", func(ctx *Context) string { return "order " + ctx.PathValue("id") })
+
+	handler := app.Handler()
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest("GET", "/orders/5", nil))
+
+	if response.Code != http.StatusOK || !strings.Contains(response.Body.String(), "order 5") {
+		t.Errorf("page not served: %d %s", response.Code, response.Body.String())
+	}
+
+	response = httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest("DELETE", "/orders/5", nil))
+
+	if response.Code != http.StatusMethodNotAllowed || !strings.Contains(response.Header().Get("Allow"), "GET") {
+		t.Errorf("wrong method not rejected: %d %q", response.Code, response.Header().Get("Allow"))
+	}
+
+	response = httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest("GET", "/missing", nil))
+
+	if response.Code != http.StatusNotFound {
+		t.Errorf("unknown path not found: %d", response.Code)
+	}
+}
+
+func TestMount(t *testing.T) {
+	app := MakeApp("en")
+	app.Mount("/admin")
+	app.Page("/", func(ctx *Context) string { return "home" })
+	app.NotFound(func(ctx *Context) string { return "custom not found" })
+
+	handler := app.Handler()
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest("GET", "/admin", nil))
+
+	if response.Code != http.StatusOK || !strings.Contains(response.Body.String(), "home") {
+		t.Errorf("mount root not served: %d %s", response.Code, response.Body.String())
+	}
+
+	if cookies := response.Result().Cookies(); len(cookies) != 1 || cookies[0].Path != "/admin" {
+		t.Errorf("session cookie not scoped to the mount root: %v", cookies)
+	}
+
+	response = httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest("GET", "/other", nil))
+
+	if response.Code != http.StatusNotFound || !strings.Contains(response.Body.String(), "custom not found") {
+		t.Errorf("NotFound hook not used outside of prefix: %d %s", response.Code, response.Body.String())
+	}
+}
+
+func TestHandledError(t *testing.T) {
+	app := MakeApp("en")
+	app.NotFound(func(ctx *Context) string { return app.HTML("Not found", "", "Nothing here") })
+
+	request := httptest.NewRequest("POST", "/removed-action", nil)
+	request.Header.Set("X-SRUI-Request", "true")
+
+	response := httptest.NewRecorder()
+	app.Handler().ServeHTTP(response, request)
+
+	if response.Header().Get("X-SRUI-Handled") != "true" || !strings.Contains(response.Body.String(), "out of date") {
+		t.Errorf("stale action not handled: %q %s", response.Header().Get("X-SRUI-Handled"), response.Body.String())
+	}
+
+	response = httptest.NewRecorder()
+	app.Handler().ServeHTTP(response, httptest.NewRequest("GET", "/missing", nil))
+
+	if response.Header().Get("X-SRUI-Handled") != "" || !strings.Contains(response.Body.String(), "Nothing here") {
+		t.Errorf("NotFound hook not used: %s", response.Body.String())
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	app := MakeApp("en")
+	remove := func(ctx *Context) string { return "" }
+
+	var rendered string
+	app.Page("/", func(ctx *Context) string {
+		rendered = ctx.Call(remove).Confirm("Delete 100% of rows?").None()
+		return rendered
+	})
+
+	app.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	if !strings.Contains(rendered, "Delete 100% of rows?") || strings.Contains(rendered, "MISSING") {
+		t.Errorf("message not rendered as it is: %s", rendered)
+	}
+
+	if strings.Contains(rendered, "<form") || strings.Contains(rendered, "&lt;form") {
+		t.Errorf("modal markup rendered into the action: %s", rendered)
+	}
+}
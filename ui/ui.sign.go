@@ -0,0 +1,277 @@
+package ui
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	signName  = "__srui_sign__"
+	tokenName = "__srui_token__"
+)
+
+var (
+	ErrSignature = errors.New("invalid signature of action values, please reload the page")
+	ErrExpired   = errors.New("action values expired, please reload the page")
+)
+
+type sealed struct {
+	path    string
+	session string
+	items   []BodyItem
+	expires time.Time
+}
+
+// Sign enables HMAC signing of values passed to actions, e.g. ctx.Call(delete, Row{ID: 5}).
+// ctx.Body then rejects requests with missing or modified values and the signed values
+// override inputs with the same name, so pass only values the user must not change.
+// Signatures are bound to the action path and the session, they can not be replayed elsewhere.
+func (app *App) Sign(key []byte) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	app.key = key
+}
+
+func (app *App) mac(key []byte, parts ...string) string {
+	hash := hmac.New(sha256.New, key)
+
+	for i, part := range parts {
+		if i > 0 {
+			hash.Write([]byte{0})
+		}
+
+		hash.Write([]byte(part))
+	}
+
+	return base64.RawURLEncoding.EncodeToString(hash.Sum(nil))
+}
+
+// secret is the key of tokens, random one is used when signing is not enabled.
+func (app *App) secret() []byte {
+	if app.key != nil {
+		return app.key
+	}
+
+	if app.random == nil {
+		app.random = make([]byte, 32)
+		rand.Read(app.random)
+	}
+
+	return app.random
+}
+
+// seal prepares values of the action for the client, server only values are kept
+// in the store and replaced by a token, everything is signed when the key is set.
+// Same values of the same action and session get the same token, so re-renders do not grow the store.
+func (app *App) seal(path string, session string, items []BodyItem, server []BodyItem) []BodyItem {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	if len(server) > 0 {
+		payload, err := json.Marshal(server)
+		if err != nil {
+			return items
+		}
+
+		now := time.Now()
+		ttl := app.Config.ValuesTTL
+		if ttl <= 0 {
+			ttl = time.Hour
+		}
+
+		if app.sealed == nil {
+			app.sealed = make(map[string]*sealed)
+		}
+
+		value := app.mac(app.secret(), path, session, string(payload))
+
+		if found, ok := app.sealed[value]; ok {
+			found.expires = now.Add(ttl)
+		} else {
+			app.sweep(now)
+			app.sealed[value] = &sealed{path: path, session: session, items: server, expires: now.Add(ttl)}
+		}
+
+		items = append(items, BodyItem{Name: tokenName, Type: "token", Value: value})
+	}
+
+	if app.key == nil {
+		return items
+	}
+
+	payload, err := json.Marshal(items)
+	if err != nil {
+		return items
+	}
+
+	data := base64.RawURLEncoding.EncodeToString(payload)
+
+	return []BodyItem{{Name: signName, Type: "sign", Value: data + "." + app.mac(app.key, path, session, data)}}
+}
+
+// sweep removes expired values, when the store is full the ones expiring first are dropped.
+func (app *App) sweep(now time.Time) {
+	limit := app.Config.ValuesLimit
+	if limit <= 0 {
+		limit = 10000
+	}
+
+	if now.Sub(app.swept) > time.Minute || len(app.sealed) >= limit {
+		for key, value := range app.sealed {
+			if now.After(value.expires) {
+				delete(app.sealed, key)
+			}
+		}
+
+		app.swept = now
+	}
+
+	for len(app.sealed) >= limit {
+		oldest := ""
+
+		for key, value := range app.sealed {
+			if oldest == "" || value.expires.Before(app.sealed[oldest].expires) {
+				oldest = key
+			}
+		}
+
+		delete(app.sealed, oldest)
+	}
+}
+
+// clientItems drops items posted by the client into fields tagged srui:"server".
+func clientItems(output any, items []BodyItem) []BodyItem {
+	t := reflect.TypeOf(output)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return items
+	}
+
+	var result []BodyItem
+
+	for _, item := range items {
+		name := item.Name
+		if i := strings.IndexAny(name, ".["); i >= 0 {
+			name = name[:i]
+		}
+
+		if field, ok := t.FieldByName(name); ok && field.Tag.Get("srui") == "server" {
+			continue
+		}
+
+		result = append(result, item)
+	}
+
+	return result
+}
+
+// unseal verifies the signature and resolves server only values,
+// returned items are client items followed by the trusted ones.
+func (app *App) unseal(path string, session string, items []BodyItem) ([]BodyItem, error) {
+	app.mu.Lock()
+	key := app.key
+	app.mu.Unlock()
+
+	var client []BodyItem
+	var trusted []BodyItem
+	var signature *BodyItem
+
+	for i, item := range items {
+		switch item.Name {
+		case signName:
+			signature = &items[i]
+
+		case tokenName:
+			if key == nil {
+				trusted = append(trusted, item)
+			}
+
+		default:
+			client = append(client, item)
+		}
+	}
+
+	if key != nil {
+		if signature == nil {
+			if len(client) == 0 {
+				return nil, nil
+			}
+
+			return nil, ErrSignature
+		}
+
+		data, mac, ok := strings.Cut(signature.Value, ".")
+		if !ok || !hmac.Equal([]byte(mac), []byte(app.mac(key, path, session, data))) {
+			return nil, ErrSignature
+		}
+
+		payload, err := base64.RawURLEncoding.DecodeString(data)
+		if err != nil {
+			return nil, ErrSignature
+		}
+
+		err = json.Unmarshal(payload, &trusted)
+		if err != nil {
+			return nil, ErrSignature
+		}
+	}
+
+	result := client
+
+	for _, item := range trusted {
+		if item.Name != tokenName {
+			result = append(result, item)
+			continue
+		}
+
+		app.mu.Lock()
+		found, ok := app.sealed[item.Value]
+		app.mu.Unlock()
+
+		if !ok || time.Now().After(found.expires) {
+			return nil, ErrExpired
+		}
+
+		if found.path != path || found.session != session {
+			return nil, ErrSignature
+		}
+
+		result = append(result, found.items...)
+	}
+
+	return result, nil
+}
+
+// SignedInput renders values of the action as hidden inputs, including the signature and the token
+// of server only values, so plain <form method="post"> works when signing is enabled.
+func (ctx *Context) SignedInput(action Callable, values ...any) string {
+	path, ok := ctx.App.path(*ctx.Callable(action))
+	if !ok {
+		return ""
+	}
+
+	result := ""
+
+	for _, item := range ctx.values(path, values) {
+		result += Hidden(item.Name, "hidden", item.Value)
+	}
+
+	return result
+}
+
+// ActionURL returns URL of the action, e.g. for action attribute of plain forms.
+func (ctx *Context) ActionURL(action Callable) string {
+	path, _ := ctx.App.path(*ctx.Callable(action))
+	return ctx.App.URL(path)
+}
@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSealSigned(t *testing.T) {
+	app := MakeApp("en")
+	app.Sign([]byte("secret"))
+
+	items := app.seal("/delete", "session", []BodyItem{{Name: "ID", Value: "5"}}, nil)
+	if len(items) != 1 || items[0].Name != signName {
+		t.Fatalf("values not signed: %+v", items)
+	}
+
+	result, err := app.unseal("/delete", "session", items)
+	if err != nil || len(result) != 1 || result[0].Value != "5" {
+		t.Fatalf("unexpected result: %+v %v", result, err)
+	}
+
+	tampered := []BodyItem{{Name: signName, Value: items[0].Value + "x"}}
+	if _, err := app.unseal("/delete", "session", tampered); err != ErrSignature {
+		t.Errorf("tampered values accepted: %v", err)
+	}
+
+	if _, err := app.unseal("/other", "session", items); err != ErrSignature {
+		t.Errorf("values accepted on other path: %v", err)
+	}
+
+	if _, err := app.unseal("/delete", "other", items); err != ErrSignature {
+		t.Errorf("values accepted in other session: %v", err)
+	}
+
+	if _, err := app.unseal("/delete", "session", []BodyItem{{Name: "ID", Value: "6"}}); err != ErrSignature {
+		t.Errorf("unsigned values accepted: %v", err)
+	}
+
+	// signed values win over inputs with the same name
+	result, err = app.unseal("/delete", "session", append([]BodyItem{{Name: "ID", Value: "6"}}, items...))
+	if err != nil || result[len(result)-1].Value != "5" {
+		t.Errorf("signed value is not last: %+v %v", result, err)
+	}
+}
+
+func TestSealServer(t *testing.T) {
+	app := MakeApp("en")
+	server := []BodyItem{{Name: "Owner", Value: "7"}}
+
+	items := app.seal("/delete", "session", nil, server)
+	if len(items) != 1 || items[0].Name != tokenName {
+		t.Fatalf("server values not replaced by token: %+v", items)
+	}
+
+	again := app.seal("/delete", "session", nil, server)
+	if again[0].Value != items[0].Value || len(app.sealed) != 1 {
+		t.Errorf("token not reused on re-render: %d entries", len(app.sealed))
+	}
+
+	result, err := app.unseal("/delete", "session", items)
+	if err != nil || len(result) != 1 || result[0].Value != "7" {
+		t.Fatalf("unexpected result: %+v %v", result, err)
+	}
+
+	if _, err := app.unseal("/other", "session", items); err != ErrSignature {
+		t.Errorf("token accepted on other path: %v", err)
+	}
+
+	if _, err := app.unseal("/delete", "other", items); err != ErrSignature {
+		t.Errorf("token accepted in other session: %v", err)
+	}
+
+	if _, err := app.unseal("/delete", "session", []BodyItem{{Name: tokenName, Value: "unknown"}}); err != ErrExpired {
+		t.Errorf("unknown token accepted: %v", err)
+	}
+
+	app.sealed[items[0].Value].expires = time.Now().Add(-time.Second)
+	if _, err := app.unseal("/delete", "session", items); err != ErrExpired {
+		t.Errorf("expired token accepted: %v", err)
+	}
+}
+
+func TestSealLimit(t *testing.T) {
+	app := MakeApp("en")
+	app.Config.ValuesLimit = 3
+
+	for _, value := range []string{"1", "2", "3", "4", "5"} {
+		app.seal("/delete", "session", nil, []BodyItem{{Name: "Owner", Value: value}})
+	}
+
+	if len(app.sealed) != 3 {
+		t.Errorf("store not capped: %d entries", len(app.sealed))
+	}
+}
+
+func TestBodyServerForged(t *testing.T) {
+	type row struct {
+		ID    int
+		Owner int `srui:"server"`
+	}
+
+	var output row
+	var err error
+
+	app := MakeApp("en")
+	action := func(ctx *Context) string {
+		output = row{}
+		err = ctx.Body(&output)
+		return ""
+	}
+
+	app.Register("POST", "/delete", &action)
+
+	post := func(body string) {
+		request := httptest.NewRequest("POST", "/delete", strings.NewReader(body))
+		request.Header.Set("Cookie", "session_id=session")
+		app.Handler().ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	post(`[{"name":"ID","value":"1"},{"name":"Owner","value":"99"}]`)
+
+	if err != nil || output.ID != 1 || output.Owner != 0 {
+		t.Errorf("forged server value bound: %+v %v", output, err)
+	}
+
+	items := app.seal("/delete", "session", nil, []BodyItem{{Name: "Owner", Value: "7"}})
+	post(`[{"name":"Owner","value":"99"},{"name":"` + tokenName + `","value":"` + items[0].Value + `"}]`)
+
+	if err != nil || output.Owner != 7 {
+		t.Errorf("sealed server value not bound: %+v %v", output, err)
+	}
+}